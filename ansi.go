@@ -0,0 +1,73 @@
+package purse
+
+import (
+	"strings"
+)
+
+// ansiLen returns the length of the ANSI escape sequence at the start of s, or 0 if there is none.
+// It recognizes CSI sequences ("\x1b[31m"), OSC sequences ending in BEL or ST, and two-byte escapes
+// made of ESC and one byte in the Fe range 0x40–0x5F. An ESC followed by anything else is a sequence
+// of its own, so the byte after it is left as text.
+func ansiLen(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7E {
+				return i + 1
+			}
+			if s[i] < 0x20 || s[i] > 0x7E {
+				return 0
+			}
+		}
+		return 0
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return 0
+	default:
+		if s[1] >= 0x40 && s[1] <= 0x5F {
+			return 2
+		}
+		return 1
+	}
+}
+
+// StripANSI removes ANSI escape sequences such as color codes from a string.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if n := ansiLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		sb.WriteByte(s[i])
+		i++
+	}
+	return sb.String()
+}
+
+// ansiCodes returns only the ANSI escape sequences of a string, concatenated.
+func ansiCodes(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if n := ansiLen(s[i:]); n > 0 {
+			sb.WriteString(s[i : i+n])
+			i += n
+			continue
+		}
+		i++
+	}
+	return sb.String()
+}
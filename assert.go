@@ -0,0 +1,100 @@
+package purse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TestingT is the part of testing.TB used by the assertion helpers, so purse does not
+// need to import the testing package.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// LinesEqualIgnoringWhitespace reports whether a and b have the same lines once runs of
+// whitespace inside each line are collapsed, surrounding whitespace is trimmed and blank
+// lines are dropped. Unlike comparing flattened strings, a line break that moved is
+// still a difference.
+func LinesEqualIgnoringWhitespace(a, b string) bool {
+	x, y := comparableLines(a), comparableLines(b)
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// comparableLines returns the non-blank lines of s with whitespace normalized.
+func comparableLines(s string) []string {
+	var out []string
+	eachLine(s, func(_ int, line string) {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			out = append(out, strings.Join(fields, " "))
+		}
+	})
+	return out
+}
+
+// DiffIgnoringIndentation compares a and b line by line with leading and trailing
+// whitespace ignored, and returns the lines that differ, or "" when they match. Lines
+// only in a are prefixed "-" and lines only in b "+", followed by their 1-based line
+// number on that side, such as "-3: return x".
+func DiffIgnoringIndentation(a, b string) string {
+	x, y := MakeLines(a), MakeLines(b)
+	n, m := len(x), len(y)
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if strings.TrimSpace(x[i]) == strings.TrimSpace(y[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out []string
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && strings.TrimSpace(x[i]) == strings.TrimSpace(y[j]):
+			i, j = i+1, j+1
+		case j == m || i < n && lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, fmt.Sprintf("-%d: %s", i+1, x[i]))
+			i++
+		default:
+			out = append(out, fmt.Sprintf("+%d: %s", j+1, y[j]))
+			j++
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// AssertContainsLine fails t unless some line of s equals line, ignoring surrounding
+// whitespace. The failure message shows the closest line found. It reports whether the
+// line was present.
+func AssertContainsLine(t TestingT, s, line string) bool {
+	t.Helper()
+	want := strings.TrimSpace(line)
+	found := false
+	eachLine(s, func(_ int, l string) {
+		found = found || strings.TrimSpace(l) == want
+	})
+	if found {
+		return true
+	}
+	if idx, _, ok := FindBestLineMatch(s, line, 1); ok {
+		t.Errorf("missing line %q; closest is line %d: %q", want, idx+1, MakeLines(s)[idx])
+	} else {
+		t.Errorf("missing line %q", want)
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package purse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var defaultBalancePairs = [][2]string{{"(", ")"}, {"[", "]"}, {"{", "}"}}
+
+// Balancer describes which delimiters must balance and which parts of the text are
+// skipped while checking them.
+type Balancer struct {
+	Pairs  [][2]string // open and close delimiters, tried in order
+	Quotes string      // characters that open and close string literals
+	// RawQuotes lists the quote characters whose literals ignore backslash escapes.
+	RawQuotes string
+	// Comments are skipped like string literals, so an apostrophe in a comment is harmless.
+	Comments CommentStyle
+}
+
+// DefaultBalancer checks (), [] and {} and skips double-quoted and backtick literals.
+// Single quotes are not treated as literals, since apostrophes in prose and comments
+// would open one.
+var DefaultBalancer = Balancer{Pairs: defaultBalancePairs, Quotes: "\"`", RawQuotes: "`"}
+
+// CodeBalancer checks (), [] and {} in C-like source such as Go, skipping comments and
+// string and rune literals.
+var CodeBalancer = Balancer{Pairs: defaultBalancePairs, Quotes: "\"'`", RawQuotes: "`", Comments: CommentStyleC}
+
+// skip returns the offset just past the string literal or comment starting at i, or
+// i when there is none there. ok is false for a literal that is never closed.
+func (b Balancer) skip(s string, i int) (end int, ok bool) {
+	if end, found := matchComment(s, i, b.Comments); found {
+		return end, true
+	}
+	quote := s[i]
+	if strings.IndexByte(b.Quotes, quote) == -1 {
+		return i, true
+	}
+	raw := strings.IndexByte(b.RawQuotes, quote) != -1
+	for j := i + 1; j < len(s); j++ {
+		if s[j] == '\\' && !raw {
+			j++
+			continue
+		}
+		if s[j] == quote {
+			return j + 1, true
+		}
+	}
+	return i, false
+}
+
+// BalanceError describes one delimiter problem found by CheckBalanced. Offset is a byte
+// offset into the checked string; Line and Col are its 1-based position.
+type BalanceError struct {
+	Offset int
+	Line   int
+	Col    int
+	Delim  string
+	Msg    string
+}
+
+// Error formats the problem with its position, such as `3:7: unexpected "}"`.
+func (e BalanceError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Col, e.Msg)
+}
+
+// IsBalanced reports whether every opening delimiter in s is closed in the right order,
+// using DefaultBalancer with pairs in place of its own when any are given.
+func IsBalanced(s string, pairs ...[2]string) bool {
+	b := DefaultBalancer
+	if len(pairs) > 0 {
+		b.Pairs = pairs
+	}
+	return b.IsBalanced(s)
+}
+
+// IsBalanced reports whether every opening delimiter in s is closed in the right order.
+// Delimiters inside skipped literals and comments are ignored, and an unterminated
+// literal makes s unbalanced.
+func (b Balancer) IsBalanced(s string) bool {
+	return len(b.check(s, true)) == 0
+}
+
+// CheckBalanced runs Check with DefaultBalancer's quotes. pairs maps opening to closing
+// delimiters; a nil map checks (), [] and {}. Use a Balancer to choose which quotes
+// count as string literals.
+func CheckBalanced(s string, pairs map[string]string) (bool, []BalanceError) {
+	list := defaultBalancePairs
+	if len(pairs) > 0 {
+		list = make([][2]string, 0, len(pairs))
+		for open, close := range pairs {
+			list = append(list, [2]string{open, close})
+		}
+		// Try longer delimiters first so "{{" is not read as two "{"
+		sort.Slice(list, func(i, j int) bool {
+			if len(list[i][0]) != len(list[j][0]) {
+				return len(list[i][0]) > len(list[j][0])
+			}
+			return list[i][0] < list[j][0]
+		})
+	}
+	b := DefaultBalancer
+	b.Pairs = list
+	return b.Check(s)
+}
+
+// Check is IsBalanced with a report: it returns every unexpected or mismatched closing
+// delimiter and unterminated string literal as it is found, followed by the opening
+// delimiters that were never closed.
+func (b Balancer) Check(s string) (bool, []BalanceError) {
+	errs := b.check(s, false)
+	return len(errs) == 0, errs
+}
+
+// check scans s for delimiter problems, stopping at the first one if firstOnly is set.
+// After a mismatched close it recovers by closing the open delimiters it skips, so a
+// single mistake is not reported again at every later delimiter.
+func (b Balancer) check(s string, firstOnly bool) []BalanceError {
+	type opened struct {
+		offset int
+		open   string
+		close  string
+	}
+	var stack []opened
+	var errs []BalanceError
+	report := func(offset int, delim, msg string) bool {
+		line, col := LineCol(s, offset)
+		errs = append(errs, BalanceError{Offset: offset, Line: line, Col: col, Delim: delim, Msg: msg})
+		return firstOnly
+	}
+	for i := 0; i < len(s); {
+		if end, ok := b.skip(s, i); !ok {
+			report(i, s[i:i+1], "unterminated string literal")
+			return errs
+		} else if end > i {
+			i = end
+			continue
+		}
+		if n := len(stack); n > 0 && strings.HasPrefix(s[i:], stack[n-1].close) {
+			i += len(stack[n-1].close)
+			stack = stack[:n-1]
+			continue
+		}
+		step := 1
+		for _, pair := range b.Pairs {
+			if strings.HasPrefix(s[i:], pair[0]) {
+				stack = append(stack, opened{offset: i, open: pair[0], close: pair[1]})
+				step = len(pair[0])
+				break
+			}
+			if !strings.HasPrefix(s[i:], pair[1]) {
+				continue
+			}
+			step = len(pair[1])
+			depth := len(stack) - 1
+			for depth >= 0 && stack[depth].close != pair[1] {
+				depth--
+			}
+			if depth == -1 {
+				if report(i, pair[1], fmt.Sprintf("unexpected %q", pair[1])) {
+					return errs
+				}
+				break
+			}
+			top := stack[len(stack)-1]
+			if report(i, pair[1], fmt.Sprintf("%q closes %q opened at offset %d before %q is closed", pair[1], stack[depth].open, stack[depth].offset, top.open)) {
+				return errs
+			}
+			stack = stack[:depth]
+			break
+		}
+		i += step
+	}
+	for _, o := range stack {
+		if report(o.offset, o.open, fmt.Sprintf("%q is never closed", o.open)) {
+			return errs
+		}
+	}
+	return errs
+}
+
+// ExtractBalanced finds the first open in s and returns the text up to its matching
+// close, using DefaultBalancer's rules for literals.
+func ExtractBalanced(s string, open, close string) (inner string, rest string, err error) {
+	return DefaultBalancer.Extract(s, open, close)
+}
+
+// Extract finds the first open in s and returns the text up to its matching close,
+// counting nested pairs and ignoring delimiters inside skipped literals and comments.
+// inner excludes the delimiters and rest is everything after the closing delimiter,
+// so a Go function body or a nested JSON object can be sliced out whole. b.Pairs is
+// not used.
+func (b Balancer) Extract(s string, open, close string) (inner string, rest string, err error) {
+	if open == "" || close == "" {
+		return "", s, fmt.Errorf("open and close delimiters must not be empty")
+	}
+	start, depth := -1, 0
+	for i := 0; i < len(s); {
+		if end, ok := b.skip(s, i); !ok {
+			return "", s, fmt.Errorf("unterminated string literal at offset %d", i)
+		} else if end > i {
+			i = end
+			continue
+		}
+		switch {
+		case depth > 0 && strings.HasPrefix(s[i:], close):
+			depth--
+			if depth == 0 {
+				return s[start:i], s[i+len(close):], nil
+			}
+			i += len(close)
+		case strings.HasPrefix(s[i:], open):
+			if depth == 0 {
+				start = i + len(open)
+			}
+			depth++
+			i += len(open)
+		default:
+			i++
+		}
+	}
+	if start == -1 {
+		return "", s, fmt.Errorf("no %q found", open)
+	}
+	return "", s, fmt.Errorf("%q at offset %d is never closed", open, start-len(open))
+}
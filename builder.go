@@ -0,0 +1,104 @@
+package purse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Builder accumulates a string and supports placeholders that are filled in after the fact.
+type Builder struct {
+	parts    []builderPart
+	tail     strings.Builder
+	reserved map[string]bool
+	values   map[string]string
+}
+
+// builderPart is either text written before a placeholder or the placeholder itself.
+type builderPart struct {
+	text        string
+	placeholder string
+}
+
+// NewBuilder creates an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{reserved: make(map[string]bool), values: make(map[string]string)}
+}
+
+// Write appends text to the builder. Text since the last placeholder is collected in a
+// strings.Builder, so many small writes stay linear.
+func (b *Builder) Write(s string) {
+	b.tail.WriteString(s)
+}
+
+// Writef appends formatted text to the builder.
+func (b *Builder) Writef(format string, args ...any) {
+	b.Write(fmt.Sprintf(format, args...))
+}
+
+// ReservePlaceholder marks the current position to be filled in later by ResolvePlaceholder.
+// It returns an error if name is empty or already reserved.
+func (b *Builder) ReservePlaceholder(name string) error {
+	if name == "" {
+		return errors.New("placeholder name is empty")
+	}
+	if b.reserved[name] {
+		return fmt.Errorf("placeholder %q is already reserved", name)
+	}
+	if b.reserved == nil {
+		b.reserved = make(map[string]bool)
+		b.values = make(map[string]string)
+	}
+	b.reserved[name] = true
+	if b.tail.Len() > 0 {
+		b.parts = append(b.parts, builderPart{text: b.tail.String()})
+		b.tail.Reset()
+	}
+	b.parts = append(b.parts, builderPart{placeholder: name})
+	return nil
+}
+
+// ResolvePlaceholder sets the value of a reserved placeholder.
+func (b *Builder) ResolvePlaceholder(name, value string) error {
+	if !b.reserved[name] {
+		return fmt.Errorf("placeholder %q was never reserved", name)
+	}
+	b.values[name] = value
+	return nil
+}
+
+// Unresolved returns the names of placeholders that have not been resolved.
+func (b *Builder) Unresolved() []string {
+	var names []string
+	for _, part := range b.parts {
+		if part.placeholder == "" {
+			continue
+		}
+		if _, ok := b.values[part.placeholder]; !ok {
+			names = append(names, part.placeholder)
+		}
+	}
+	return names
+}
+
+// Build returns the built string, or an error if any placeholder is unresolved.
+func (b *Builder) Build() (string, error) {
+	if missing := b.Unresolved(); len(missing) > 0 {
+		return "", fmt.Errorf("unresolved placeholders: %s", strings.Join(missing, ", "))
+	}
+	return b.String(), nil
+}
+
+// String returns the built string, rendering unresolved placeholders as empty.
+func (b *Builder) String() string {
+	var sb strings.Builder
+	for _, part := range b.parts {
+		if part.placeholder != "" {
+			sb.WriteString(b.values[part.placeholder])
+			continue
+		}
+		sb.WriteString(part.text)
+	}
+	sb.WriteString(b.tail.String())
+	return sb.String()
+}
@@ -0,0 +1,172 @@
+// Package bytes mirrors purse's line-processing and replace functions for []byte, so
+// callers holding file contents or network buffers can transform them without
+// converting to a string and back on every call. It is usually imported under an
+// alias, such as pbytes, to keep the standard library's bytes package in reach.
+//
+// MakeLines returns subslices of its input. Other results are freshly allocated, except
+// that a function with nothing to change may return its input as is.
+package bytes
+
+import (
+	"bytes"
+)
+
+var newline = []byte("\n")
+
+// MakeLines splits b into lines. The lines are subslices of b, not copies.
+func MakeLines(b []byte) [][]byte {
+	return bytes.Split(b, newline)
+}
+
+// JoinLines joins lines into a single buffer separated by newlines.
+func JoinLines(lines [][]byte) []byte {
+	return bytes.Join(lines, newline)
+}
+
+// CountLines returns the number of lines MakeLines would produce, or 0 for an empty buffer.
+func CountLines(b []byte) int {
+	if len(b) == 0 {
+		return 0
+	}
+	return bytes.Count(b, newline) + 1
+}
+
+// eachLine calls fn with every line of b, exactly as MakeLines would split it, without
+// allocating a slice of lines.
+func eachLine(b []byte, fn func(i int, line []byte)) {
+	for i := 0; ; i++ {
+		j := bytes.IndexByte(b, '\n')
+		if j == -1 {
+			fn(i, b)
+			return
+		}
+		fn(i, b[:j])
+		b = b[j+1:]
+	}
+}
+
+// MapLines replaces every line of b with the result of fn, which receives the line and its index.
+func MapLines(b []byte, fn func(line []byte, i int) []byte) []byte {
+	out := make([]byte, 0, len(b))
+	eachLine(b, func(i int, line []byte) {
+		if i > 0 {
+			out = append(out, '\n')
+		}
+		out = append(out, fn(line, i)...)
+	})
+	return out
+}
+
+// FilterLines keeps only the lines of b for which keep returns true.
+func FilterLines(b []byte, keep func(line []byte, i int) bool) []byte {
+	out := make([]byte, 0, len(b))
+	first := true
+	eachLine(b, func(i int, line []byte) {
+		if !keep(line, i) {
+			return
+		}
+		if !first {
+			out = append(out, '\n')
+		}
+		first = false
+		out = append(out, line...)
+	})
+	return out
+}
+
+// PrefixLines adds a prefix to each line of b.
+func PrefixLines(b, prefix []byte) []byte {
+	out := make([]byte, 0, len(b)+CountLines(b)*len(prefix))
+	eachLine(b, func(i int, line []byte) {
+		if i > 0 {
+			out = append(out, '\n')
+		}
+		out = append(out, prefix...)
+		out = append(out, line...)
+	})
+	return out
+}
+
+// RemoveEmptyLines removes all blank lines from b.
+func RemoveEmptyLines(b []byte) []byte {
+	return FilterLines(b, func(line []byte, _ int) bool {
+		return len(bytes.TrimSpace(line)) > 0
+	})
+}
+
+// TrimLeadingSpaces removes leading spaces from every line of b.
+func TrimLeadingSpaces(b []byte) []byte {
+	return MapLines(b, func(line []byte, _ int) []byte {
+		return bytes.TrimLeft(line, " ")
+	})
+}
+
+// TrimTrailingSpaces removes trailing spaces and tabs from every line of b.
+func TrimTrailingSpaces(b []byte) []byte {
+	return MapLines(b, func(line []byte, _ int) []byte {
+		return bytes.TrimRight(line, " \t")
+	})
+}
+
+// Flatten removes leading spaces and tabs from every line of b and joins the lines
+// without separators.
+func Flatten(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	eachLine(b, func(_ int, line []byte) {
+		out = append(out, bytes.TrimLeft(line, " \t")...)
+	})
+	return out
+}
+
+// replaceAt returns a new buffer with b[i:i+n] replaced by new.
+func replaceAt(b []byte, i, n int, new []byte) []byte {
+	out := make([]byte, 0, len(b)-n+len(new))
+	out = append(out, b[:i]...)
+	out = append(out, new...)
+	return append(out, b[i+n:]...)
+}
+
+// ReplaceFirstInstanceOf replaces the first occurrence of old with new. b is returned
+// unchanged when old does not occur.
+func ReplaceFirstInstanceOf(b, old, new []byte) []byte {
+	i := bytes.Index(b, old)
+	if i == -1 {
+		return b
+	}
+	return replaceAt(b, i, len(old), new)
+}
+
+// ReplaceLastSubStr replaces the last occurrence of old with new. b is returned
+// unchanged when old does not occur.
+func ReplaceLastSubStr(b, old, new []byte) []byte {
+	i := bytes.LastIndex(b, old)
+	if i == -1 {
+		return b
+	}
+	return replaceAt(b, i, len(old), new)
+}
+
+// ReplaceBetween rewrites the content inside every non-overlapping start/end pair with
+// the result of fn, keeping the delimiters in place. fn receives a subslice of b.
+func ReplaceBetween(b, start, end []byte, fn func(inner []byte) []byte) []byte {
+	if len(start) == 0 || len(end) == 0 {
+		return b
+	}
+	out := make([]byte, 0, len(b))
+	for {
+		i := bytes.Index(b, start)
+		if i == -1 {
+			break
+		}
+		open := i + len(start)
+		j := bytes.Index(b[open:], end)
+		if j == -1 {
+			break
+		}
+		out = append(out, b[:open]...)
+		out = append(out, fn(b[open:open+j])...)
+		out = append(out, end...)
+		b = b[open+j+len(end):]
+	}
+	return append(out, b...)
+}
@@ -0,0 +1,59 @@
+package bytes_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phillip-england/purse"
+	pbytes "github.com/phillip-england/purse/bytes"
+)
+
+func TestMirrorsStringFunctions(t *testing.T) {
+	inputs := []string{"", "\n", "a", "  a\n\n\tb  \n c", "x\n\n", "<<a>> and <<b>> and <<c"}
+	for _, s := range inputs {
+		b := []byte(s)
+		checks := []struct {
+			name string
+			got  []byte
+			want string
+		}{
+			{"JoinLines(MakeLines)", pbytes.JoinLines(pbytes.MakeLines(b)), purse.JoinLines(purse.MakeLines(s))},
+			{"PrefixLines", pbytes.PrefixLines(b, []byte("> ")), purse.PrefixLines(s, "> ")},
+			{"RemoveEmptyLines", pbytes.RemoveEmptyLines(b), purse.RemoveEmptyLines(s)},
+			{"TrimLeadingSpaces", pbytes.TrimLeadingSpaces(b), purse.TrimLeadingSpaces(s)},
+			{"TrimTrailingSpaces", pbytes.TrimTrailingSpaces(b), purse.TrimTrailingSpaces(s)},
+			{"Flatten", pbytes.Flatten(b), purse.Flatten(s)},
+			{"ReplaceFirstInstanceOf", pbytes.ReplaceFirstInstanceOf(b, []byte("a"), []byte("AA")), purse.ReplaceFirstInstanceOf(s, "a", "AA")},
+			{"ReplaceLastSubStr", pbytes.ReplaceLastSubStr(b, []byte("a"), []byte("")), purse.ReplaceLastSubStr(s, "a", "")},
+			{"ReplaceBetween", pbytes.ReplaceBetween(b, []byte("<<"), []byte(">>"), func(inner []byte) []byte {
+				return []byte(strings.ToUpper(string(inner)))
+			}), purse.ReplaceBetween(s, "<<", ">>", strings.ToUpper)},
+		}
+		for _, c := range checks {
+			if string(c.got) != c.want {
+				t.Errorf("%s(%q) = %q, want %q", c.name, s, c.got, c.want)
+			}
+		}
+		if got, want := pbytes.CountLines(b), purse.CountLines(s); got != want {
+			t.Errorf("CountLines(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestMakeLinesDoesNotCopy(t *testing.T) {
+	b := []byte("ab\ncd")
+	lines := pbytes.MakeLines(b)
+	lines[1][0] = 'X'
+	if string(b) != "ab\nXd" {
+		t.Errorf("MakeLines copied its input: %q", b)
+	}
+}
+
+func TestReplaceDoesNotAliasInput(t *testing.T) {
+	b := []byte("one two")
+	out := pbytes.ReplaceFirstInstanceOf(b, []byte("one"), []byte("1"))
+	out[0] = 'X'
+	if string(b) != "one two" {
+		t.Errorf("ReplaceFirstInstanceOf modified its input: %q", b)
+	}
+}
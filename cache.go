@@ -0,0 +1,103 @@
+package purse
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultCacheSize is the number of entries kept by the cache behind Cached.
+const DefaultCacheSize = 1024
+
+// Cache is a bounded least-recently-used cache of string transformations, keyed by
+// the Hash of the input. Inputs are kept alongside their results so hash collisions
+// are detected rather than returning another input's result. It is safe for
+// concurrent use.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[uint64]*list.Element
+	order    *list.List // most recently used at the front
+}
+
+type cacheEntry struct {
+	hash  uint64
+	key   string
+	value string
+}
+
+// NewCache creates a Cache holding at most capacity entries. A capacity below 1 is
+// treated as 1.
+func NewCache(capacity int) *Cache {
+	return &Cache{
+		capacity: max(capacity, 1),
+		items:    make(map[uint64]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the value stored for key and marks it as recently used.
+func (c *Cache) Get(key string) (string, bool) {
+	h := Hash(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[h]
+	if !ok || el.Value.(*cacheEntry).key != key {
+		return "", false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).value, true
+}
+
+// Put stores value for key, evicting the least recently used entry when the cache is
+// full. An entry whose key hashes the same as key is replaced.
+func (c *Cache) Put(key, value string) {
+	h := Hash(key)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[h]; ok {
+		el.Value = &cacheEntry{hash: h, key: key, value: value}
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[h] = c.order.PushFront(&cacheEntry{hash: h, key: key, value: value})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).hash)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Purge removes every entry from the cache.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[uint64]*list.Element)
+	c.order.Init()
+}
+
+// Memoize returns a function that answers from the cache and calls fn only on a miss.
+// fn runs without the cache locked, so concurrent misses on the same input may each
+// call it; fn must therefore be a pure function of its input.
+func (c *Cache) Memoize(fn func(string) string) func(string) string {
+	return func(s string) string {
+		if v, ok := c.Get(s); ok {
+			return v
+		}
+		v := fn(s)
+		c.Put(s, v)
+		return v
+	}
+}
+
+// Cached memoizes fn in a new Cache of DefaultCacheSize entries. Use NewCache and
+// Memoize to choose another size.
+func Cached(fn func(string) string) func(string) string {
+	return NewCache(DefaultCacheSize).Memoize(fn)
+}
@@ -0,0 +1,167 @@
+package purse
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CappedOption configures a CappedBuffer.
+type CappedOption func(*CappedBuffer)
+
+// MaxBytes limits the bytes a CappedBuffer retains, newlines and the pending partial
+// line included. When the limit is reached, the oldest tail lines are dropped first.
+// Lines longer than the whole budget are never retained.
+func MaxBytes(n int) CappedOption {
+	return func(b *CappedBuffer) {
+		b.maxBytes = n
+	}
+}
+
+// maxCappedLine is the longest line a CappedBuffer without MaxBytes retains; longer
+// lines are cut to it.
+const maxCappedLine = 64 << 10
+
+// CappedBuffer accumulates text while keeping only the first and last lines of it, so
+// the output of a long-running command can be collected without growing without bound.
+// Lines dropped from the middle are replaced by a marker saying how many were omitted.
+// Without MaxBytes, lines are cut to 64 KiB. It is safe for concurrent use.
+type CappedBuffer struct {
+	mu        sync.Mutex
+	keepFirst int
+	keepLast  int
+	maxBytes  int
+	head      []string
+	headDone  bool // set once any line skips the head, so later lines keep their order
+	tail      []string
+	size      int
+	partial   string
+	cut       bool // set when bytes of the partial line were discarded
+	omitted   int
+}
+
+// NewCappedBuffer creates a CappedBuffer that keeps the first keepFirst and the last
+// keepLast lines appended to it.
+func NewCappedBuffer(keepFirst, keepLast int, opts ...CappedOption) *CappedBuffer {
+	b := &CappedBuffer{keepFirst: max(keepFirst, 0), keepLast: max(keepLast, 0)}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Append adds text to the buffer. Text after the last newline is held until the line
+// is completed by a later Append.
+func (b *CappedBuffer) Append(s string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for {
+		i := strings.IndexByte(s, '\n')
+		if i == -1 {
+			break
+		}
+		b.extendPartial(s[:i])
+		b.addLine(b.partial, b.cut)
+		b.partial, b.cut = "", false
+		s = s[i+1:]
+	}
+	b.extendPartial(s)
+	b.fitPartial()
+}
+
+// extendPartial adds s to the pending line, discarding whatever goes past the line limit.
+func (b *CappedBuffer) extendPartial(s string) {
+	limit := maxCappedLine
+	if b.maxBytes > 0 {
+		limit = b.maxBytes
+	}
+	if room := max(limit-len(b.partial), 0); len(s) > room {
+		s = s[:room]
+		b.cut = true
+	}
+	b.partial += s
+}
+
+// fitPartial keeps the pending line within MaxBytes, dropping tail lines first and
+// then cutting the line itself.
+func (b *CappedBuffer) fitPartial() {
+	for len(b.tail) > 0 && b.overBudget(len(b.partial)) {
+		b.dropTailLine()
+	}
+	if b.overBudget(len(b.partial)) {
+		b.partial = b.partial[:max(b.maxBytes-b.size, 0)]
+		b.cut = true
+	}
+}
+
+// Write appends p to the buffer so it can be used as an io.Writer. It never fails.
+func (b *CappedBuffer) Write(p []byte) (int, error) {
+	b.Append(string(p))
+	return len(p), nil
+}
+
+// addLine retains a complete line in the head or tail, dropping tail lines to stay
+// within the limits. A cut line did not fit in MaxBytes, so it is dropped along with
+// the tail, as any oversized line is.
+func (b *CappedBuffer) addLine(line string, cut bool) {
+	if cut && b.maxBytes > 0 {
+		b.headDone = true
+		for len(b.tail) > 0 {
+			b.dropTailLine()
+		}
+		b.omitted++
+		return
+	}
+	cost := len(line) + 1
+	if !b.headDone && len(b.head) < b.keepFirst && !b.overBudget(cost) {
+		b.head = append(b.head, line)
+		b.size += cost
+		return
+	}
+	b.headDone = true
+	b.tail = append(b.tail, line)
+	b.size += cost
+	for len(b.tail) > 0 && (len(b.tail) > b.keepLast || b.overBudget(0)) {
+		b.dropTailLine()
+	}
+}
+
+// dropTailLine drops the oldest tail line and counts it as omitted.
+func (b *CappedBuffer) dropTailLine() {
+	b.size -= len(b.tail[0]) + 1
+	b.tail = b.tail[1:]
+	b.omitted++
+}
+
+// overBudget reports whether retaining extra additional bytes would exceed MaxBytes.
+func (b *CappedBuffer) overBudget(extra int) bool {
+	return b.maxBytes > 0 && b.size+extra > b.maxBytes
+}
+
+// Omitted returns how many complete lines have been dropped so far.
+func (b *CappedBuffer) Omitted() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.omitted
+}
+
+// String returns the retained head lines, an omission marker if any lines were
+// dropped, the retained tail lines and any pending partial line.
+func (b *CappedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var sb strings.Builder
+	for _, line := range b.head {
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	if b.omitted > 0 {
+		fmt.Fprintf(&sb, "... %d %s omitted ...\n", b.omitted, Pluralize("line", b.omitted))
+	}
+	for _, line := range b.tail {
+		sb.WriteString(line)
+		sb.WriteByte('\n')
+	}
+	sb.WriteString(b.partial)
+	return sb.String()
+}
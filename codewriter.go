@@ -0,0 +1,56 @@
+package purse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CodeWriter builds generated code line by line, tracking the current indentation.
+type CodeWriter struct {
+	sb    strings.Builder
+	unit  string
+	depth int
+}
+
+// NewCodeWriter creates a CodeWriter that indents with unit, such as "\t" or "  ".
+func NewCodeWriter(unit string) *CodeWriter {
+	return &CodeWriter{unit: unit}
+}
+
+// Line writes a formatted line at the current indentation, followed by a newline.
+// Empty lines are written without indentation.
+func (w *CodeWriter) Line(format string, args ...any) {
+	for _, l := range MakeLines(fmt.Sprintf(format, args...)) {
+		if l != "" {
+			w.sb.WriteString(strings.Repeat(w.unit, w.depth))
+			w.sb.WriteString(l)
+		}
+		w.sb.WriteByte('\n')
+	}
+}
+
+// Indent increases the indentation of following lines by one level.
+func (w *CodeWriter) Indent() {
+	w.depth++
+}
+
+// Dedent decreases the indentation of following lines by one level.
+func (w *CodeWriter) Dedent() {
+	if w.depth > 0 {
+		w.depth--
+	}
+}
+
+// Block writes open, runs fn one level deeper, then writes close.
+func (w *CodeWriter) Block(open, close string, fn func()) {
+	w.Line("%s", open)
+	w.Indent()
+	fn()
+	w.Dedent()
+	w.Line("%s", close)
+}
+
+// String returns everything written so far.
+func (w *CodeWriter) String() string {
+	return w.sb.String()
+}
@@ -0,0 +1,84 @@
+package purse
+
+import (
+	"strings"
+)
+
+// CommentStyle describes the comment and string literal syntax of a language.
+type CommentStyle struct {
+	Line      []string    // markers that comment out the rest of a line
+	Block     [][2]string // open and close markers of block comments
+	Quotes    string      // characters that open and close string literals
+	RawQuotes string      // the subset of Quotes whose literals ignore backslash escapes
+}
+
+var (
+	CommentStyleC     = CommentStyle{Line: []string{"//"}, Block: [][2]string{{"/*", "*/"}}, Quotes: "\"'`", RawQuotes: "`"}
+	CommentStyleShell = CommentStyle{Line: []string{"#"}, Quotes: "\"'", RawQuotes: "'"}
+	CommentStyleHTML  = CommentStyle{Block: [][2]string{{"<!--", "-->"}}}
+	CommentStyleSQL   = CommentStyle{Line: []string{"--"}, Block: [][2]string{{"/*", "*/"}}, Quotes: "\"'", RawQuotes: "\"'"}
+)
+
+// StripComments removes comments from s, leaving anything inside string literals untouched.
+// Line comments are removed up to, but not including, the newline that ends them.
+func StripComments(s string, style CommentStyle) string {
+	var sb strings.Builder
+	var quote byte
+	i := 0
+	for i < len(s) {
+		ch := s[i]
+		if quote != 0 {
+			sb.WriteByte(ch)
+			// Raw literals, such as Go backticks or shell single quotes, have no escapes
+			if ch == '\\' && strings.IndexByte(style.RawQuotes, quote) == -1 && i+1 < len(s) {
+				sb.WriteByte(s[i+1])
+				i += 2
+				continue
+			}
+			if ch == quote {
+				quote = 0
+			}
+			i++
+			continue
+		}
+		if strings.IndexByte(style.Quotes, ch) != -1 {
+			quote = ch
+			sb.WriteByte(ch)
+			i++
+			continue
+		}
+		if end, ok := matchComment(s, i, style); ok {
+			i = end
+			continue
+		}
+		sb.WriteByte(ch)
+		i++
+	}
+	return sb.String()
+}
+
+// matchComment returns the offset just past a comment starting at i.
+func matchComment(s string, i int, style CommentStyle) (int, bool) {
+	rest := s[i:]
+	for _, block := range style.Block {
+		if !strings.HasPrefix(rest, block[0]) {
+			continue
+		}
+		end := strings.Index(rest[len(block[0]):], block[1])
+		if end == -1 {
+			return len(s), true
+		}
+		return i + len(block[0]) + end + len(block[1]), true
+	}
+	for _, marker := range style.Line {
+		if !strings.HasPrefix(rest, marker) {
+			continue
+		}
+		end := strings.IndexByte(rest, '\n')
+		if end == -1 {
+			return len(s), true
+		}
+		return i + end, true
+	}
+	return 0, false
+}
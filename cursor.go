@@ -0,0 +1,84 @@
+package purse
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Cursor walks through a string once, extracting pieces as it goes.
+type Cursor struct {
+	src string
+	pos int
+}
+
+// NewCursor creates a Cursor positioned at the start of s.
+func NewCursor(s string) *Cursor {
+	return &Cursor{src: s}
+}
+
+// Pos returns the current byte offset of the cursor.
+func (c *Cursor) Pos() int {
+	return c.pos
+}
+
+// Remaining returns the unread portion of the string.
+func (c *Cursor) Remaining() string {
+	return c.src[c.pos:]
+}
+
+// Done reports whether the cursor has reached the end of the string.
+func (c *Cursor) Done() bool {
+	return c.pos >= len(c.src)
+}
+
+// SeekTo moves the cursor to the next occurrence of substr.
+func (c *Cursor) SeekTo(substr string) bool {
+	index := strings.Index(c.src[c.pos:], substr)
+	if index == -1 {
+		return false
+	}
+	c.pos += index
+	return true
+}
+
+// ReadUntil returns the text up to the next occurrence of substr and moves the cursor onto it.
+func (c *Cursor) ReadUntil(substr string) (string, bool) {
+	start := c.pos
+	if !c.SeekTo(substr) {
+		return "", false
+	}
+	return c.src[start:c.pos], true
+}
+
+// ReadBetween returns the next substring spanning start to end, delimiters included,
+// and moves the cursor past it.
+func (c *Cursor) ReadBetween(start, end string) (string, bool) {
+	rest := c.src[c.pos:]
+	found, ok := TargetSearch(rest, start, end)
+	if !ok {
+		return "", false
+	}
+	c.pos += strings.Index(rest, start) + len(found)
+	return found, true
+}
+
+// Context returns up to radius runes on either side of a byte offset in s, along with
+// the offset's position within the returned excerpt. The offset is clamped to s and
+// moved back to the start of the rune it falls inside.
+func Context(s string, offset, radius int) (string, int) {
+	offset = max(0, min(offset, len(s)))
+	for offset > 0 && offset < len(s) && !utf8.RuneStart(s[offset]) {
+		offset--
+	}
+	start := offset
+	for i := 0; i < radius && start > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(s[:start])
+		start -= size
+	}
+	end := offset
+	for i := 0; i < radius && end < len(s); i++ {
+		_, size := utf8.DecodeRuneInString(s[end:])
+		end += size
+	}
+	return s[start:end], offset - start
+}
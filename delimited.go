@@ -0,0 +1,60 @@
+package purse
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SplitCSVLine splits one CSV record into its fields, honouring quoted fields with
+// embedded commas, doubled quotes and line breaks.
+func SplitCSVLine(s string) ([]string, error) {
+	return splitDelimitedLine(s, ',')
+}
+
+// JoinCSVLine joins fields into one CSV record, quoting the fields that need it.
+// The result has no trailing newline.
+func JoinCSVLine(fields []string) string {
+	return joinDelimitedLine(fields, ',')
+}
+
+// SplitTSVLine is SplitCSVLine for tab-separated records.
+func SplitTSVLine(s string) ([]string, error) {
+	return splitDelimitedLine(s, '\t')
+}
+
+// JoinTSVLine is JoinCSVLine for tab-separated records.
+func JoinTSVLine(fields []string) string {
+	return joinDelimitedLine(fields, '\t')
+}
+
+// splitDelimitedLine reads exactly one record from s using comma as the separator.
+func splitDelimitedLine(s string, comma rune) ([]string, error) {
+	r := csv.NewReader(strings.NewReader(s))
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	fields, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		// encoding/csv skips empty lines, but an empty line is a record with one empty field
+		return []string{""}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Read(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("expected a single record in %q", s)
+	}
+	return fields, nil
+}
+
+// joinDelimitedLine writes fields as one record using comma as the separator.
+func joinDelimitedLine(fields []string, comma rune) string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Comma = comma
+	w.Write(fields)
+	w.Flush()
+	return strings.TrimSuffix(sb.String(), "\n")
+}
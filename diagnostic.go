@@ -0,0 +1,48 @@
+package purse
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LineCol converts a byte offset into a 1-based line number and 1-based rune column.
+// Offsets past the end of s are clamped.
+func LineCol(s string, offset int) (line, col int) {
+	offset = max(0, min(offset, len(s)))
+	lineStart := strings.LastIndex(s[:offset], "\n") + 1
+	line = strings.Count(s[:offset], "\n") + 1
+	col = utf8.RuneCountInString(s[lineStart:offset]) + 1
+	return line, col
+}
+
+// RenderDiagnostic renders the line containing a byte offset with a "^~~~" marker under
+// the token at that offset, followed by msg. Tabs before the marker are kept so it lines
+// up regardless of tab width.
+func RenderDiagnostic(s string, offset int, msg string) string {
+	offset = max(0, min(offset, len(s)))
+	lineStart := strings.LastIndex(s[:offset], "\n") + 1
+	lineEnd := len(s)
+	if i := strings.IndexByte(s[offset:], '\n'); i != -1 {
+		lineEnd = offset + i
+	}
+	line := s[lineStart:lineEnd]
+	var pad strings.Builder
+	for _, r := range s[lineStart:offset] {
+		if r == '\t' {
+			pad.WriteRune('\t')
+			continue
+		}
+		pad.WriteString(strings.Repeat(" ", RuneWidth(r)))
+	}
+	token := s[offset:lineEnd]
+	if i := strings.IndexFunc(token, unicode.IsSpace); i != -1 {
+		token = token[:i]
+	}
+	tildes := max(VisualWidth(token)-1, 0)
+	marker := pad.String() + "^" + strings.Repeat("~", tildes)
+	if msg != "" {
+		marker += " " + msg
+	}
+	return line + "\n" + marker
+}
@@ -0,0 +1,130 @@
+package purse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MovedItem is an item found in both slices but at a different relative position.
+type MovedItem struct {
+	Item string
+	From int // index in want
+	To   int // index in got
+}
+
+// SliceDiff describes how got differs from want.
+type SliceDiff struct {
+	Added   []string
+	Removed []string
+	Moved   []MovedItem
+}
+
+// DiffOption configures DiffSlices.
+type DiffOption func(*diffConfig)
+
+type diffConfig struct {
+	ignoreOrder bool
+}
+
+// IgnoreOrder makes DiffSlices compare the slices as multisets, never reporting moves.
+func IgnoreOrder() DiffOption {
+	return func(c *diffConfig) {
+		c.ignoreOrder = true
+	}
+}
+
+// Equal reports whether the diff found no differences.
+func (d SliceDiff) Equal() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Moved) == 0
+}
+
+// String formats the diff with one item per line: "+" for added, "-" for removed
+// and "~" for moved.
+func (d SliceDiff) String() string {
+	var lines []string
+	for _, item := range d.Removed {
+		lines = append(lines, "- "+item)
+	}
+	for _, item := range d.Added {
+		lines = append(lines, "+ "+item)
+	}
+	for _, m := range d.Moved {
+		lines = append(lines, fmt.Sprintf("~ %s (%d -> %d)", m.Item, m.From, m.To))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DiffSlices compares two slices, treating repeated items as distinct occurrences.
+// Items kept in both are reported as moved when they fall outside the longest run of
+// items that appear in the same relative order.
+func DiffSlices(want, got []string, opts ...DiffOption) SliceDiff {
+	var cfg diffConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	var diff SliceDiff
+	// Pair the k-th occurrence of an item in want with its k-th occurrence in got
+	available := make(map[string][]int)
+	for i, item := range got {
+		available[item] = append(available[item], i)
+	}
+	var pairs [][2]int
+	matchedGot := make([]bool, len(got))
+	for i, item := range want {
+		if idx := available[item]; len(idx) > 0 {
+			pairs = append(pairs, [2]int{i, idx[0]})
+			matchedGot[idx[0]] = true
+			available[item] = idx[1:]
+			continue
+		}
+		diff.Removed = append(diff.Removed, item)
+	}
+	for i, item := range got {
+		if !matchedGot[i] {
+			diff.Added = append(diff.Added, item)
+		}
+	}
+	if cfg.ignoreOrder {
+		return diff
+	}
+	// Pairs are ordered by want index, so the longest increasing run of got indexes
+	// is the largest set of items that kept their relative order
+	inOrder := longestIncreasing(pairs)
+	for i, p := range pairs {
+		if !inOrder[i] {
+			diff.Moved = append(diff.Moved, MovedItem{Item: want[p[0]], From: p[0], To: p[1]})
+		}
+	}
+	return diff
+}
+
+// longestIncreasing marks the pairs that form the longest subsequence with increasing second values.
+// It uses patience sorting: tails[k] is the pair ending the smallest-valued increasing run of
+// length k+1 seen so far, found by binary search, so the whole pass is O(n log n).
+func longestIncreasing(pairs [][2]int) []bool {
+	prev := make([]int, len(pairs))
+	var tails []int
+	for i, p := range pairs {
+		k := sort.Search(len(tails), func(k int) bool {
+			return pairs[tails[k]][1] >= p[1]
+		})
+		prev[i] = -1
+		if k > 0 {
+			prev[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	keep := make([]bool, len(pairs))
+	if len(tails) == 0 {
+		return keep
+	}
+	for i := tails[len(tails)-1]; i != -1; i = prev[i] {
+		keep[i] = true
+	}
+	return keep
+}
@@ -0,0 +1,39 @@
+package purse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Edit replaces the bytes between Start and End with Text.
+type Edit struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// ReplaceAtOffsets applies a batch of edits to s in a single pass.
+// Offsets refer to the original string and edits may not overlap.
+func ReplaceAtOffsets(s string, edits []Edit) (string, error) {
+	sorted := make([]Edit, len(edits))
+	copy(sorted, edits)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+	var sb strings.Builder
+	pos := 0
+	for _, edit := range sorted {
+		if edit.Start < 0 || edit.End < edit.Start || edit.End > len(s) {
+			return "", fmt.Errorf("edit [%d:%d] is out of range for a string of length %d", edit.Start, edit.End, len(s))
+		}
+		if edit.Start < pos {
+			return "", fmt.Errorf("edit [%d:%d] overlaps a previous edit ending at %d", edit.Start, edit.End, pos)
+		}
+		sb.WriteString(s[pos:edit.Start])
+		sb.WriteString(edit.Text)
+		pos = edit.End
+	}
+	sb.WriteString(s[pos:])
+	return sb.String(), nil
+}
@@ -0,0 +1,198 @@
+package purse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// EscapeContext names a target that strings can be escaped for.
+type EscapeContext int
+
+const (
+	EscapeContextHTML EscapeContext = iota
+	EscapeContextShell
+	EscapeContextCSV
+	EscapeContextJSON
+)
+
+type escaper struct {
+	escape   func(string) string
+	unescape func(string) (string, error)
+}
+
+var escapers = map[EscapeContext]escaper{
+	EscapeContextHTML:  {EscapeHTML, func(s string) (string, error) { return UnescapeHTML(s), nil }},
+	EscapeContextShell: {EscapeShellArg, UnescapeShellArg},
+	EscapeContextCSV:   {EscapeCSVField, UnescapeCSVField},
+	EscapeContextJSON:  {EscapeJSONString, UnescapeJSONString},
+}
+
+// Escape escapes a string for safe embedding in the given context.
+func Escape(s string, ctx EscapeContext) string {
+	e, ok := escapers[ctx]
+	if !ok {
+		return s
+	}
+	return e.escape(s)
+}
+
+// Unescape reverses Escape for the given context.
+func Unescape(s string, ctx EscapeContext) (string, error) {
+	e, ok := escapers[ctx]
+	if !ok {
+		return "", fmt.Errorf("unknown escape context %d", ctx)
+	}
+	return e.unescape(s)
+}
+
+// EscapeHTML escapes <, >, &, ' and " for use in HTML text and attribute values.
+func EscapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// UnescapeHTML turns HTML entities back into the characters they stand for.
+func UnescapeHTML(s string) string {
+	return html.UnescapeString(s)
+}
+
+// EscapeShellArg quotes a string so a POSIX shell reads it as a single literal argument.
+func EscapeShellArg(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,+@%", r))
+	}) == -1 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// UnescapeShellArg reads a single shell word back into the string it stands for.
+func UnescapeShellArg(s string) (string, error) {
+	words, err := SplitArgs(s)
+	if err != nil {
+		return "", err
+	}
+	if len(words) != 1 {
+		return "", fmt.Errorf("expected a single shell word, found %d", len(words))
+	}
+	return words[0], nil
+}
+
+// EscapeCSVField quotes a CSV field when it contains commas, quotes, line breaks or
+// surrounding spaces, doubling any embedded quotes.
+func EscapeCSVField(s string) string {
+	if !strings.ContainsAny(s, ",\"\r\n") && strings.TrimSpace(s) == s {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// UnescapeCSVField reverses EscapeCSVField.
+func UnescapeCSVField(s string) (string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		if strings.Contains(s, `"`) {
+			return "", fmt.Errorf("bare quote in unquoted field %q", s)
+		}
+		return s, nil
+	}
+	if len(s) < 2 || !strings.HasSuffix(s, `"`) {
+		return "", fmt.Errorf("unterminated quoted field %q", s)
+	}
+	inner := s[1 : len(s)-1]
+	if strings.Contains(strings.ReplaceAll(inner, `""`, ""), `"`) {
+		return "", fmt.Errorf("unescaped quote in field %q", s)
+	}
+	return strings.ReplaceAll(inner, `""`, `"`), nil
+}
+
+// EscapeJSONString escapes a string for use between the quotes of a JSON string.
+// The surrounding quotes are not included.
+func EscapeJSONString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	out := strings.TrimSuffix(buf.String(), "\n")
+	return out[1 : len(out)-1]
+}
+
+// UnescapeJSONString reverses EscapeJSONString.
+func UnescapeJSONString(s string) (string, error) {
+	var out string
+	if err := json.Unmarshal([]byte(`"`+s+`"`), &out); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// DecodeEscapes interprets Go-style escape sequences such as \n, \t, \xNN, \uNNNN and
+// \\ in a plain string, the way strconv.Unquote would without needing surrounding quotes.
+// Quote characters may appear escaped or bare.
+func DecodeEscapes(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			i++
+			continue
+		}
+		if i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\'') {
+			sb.WriteByte(s[i+1])
+			i += 2
+			continue
+		}
+		value, multibyte, tail, err := strconv.UnquoteChar(s[i:], 0)
+		if err != nil {
+			end := min(i+2, len(s))
+			return "", fmt.Errorf("invalid escape %q at offset %d", s[i:end], i)
+		}
+		if multibyte {
+			sb.WriteRune(value)
+		} else {
+			// \xNN and octal escapes name a single byte, not a code point
+			sb.WriteByte(byte(value))
+		}
+		i = len(s) - len(tail)
+	}
+	return sb.String(), nil
+}
+
+// EncodeEscapes is the inverse of DecodeEscapes. Backslashes, control characters,
+// non-printable runes and invalid UTF-8 bytes are escaped; everything else, including
+// printable non-ASCII text and quotes, is kept as written.
+func EncodeEscapes(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&sb, `\x%02x`, s[i])
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r < 0x80 && !unicode.IsPrint(r):
+			fmt.Fprintf(&sb, `\x%02x`, r)
+		case !unicode.IsPrint(r) && r <= 0xFFFF:
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		case !unicode.IsPrint(r):
+			fmt.Fprintf(&sb, `\U%08x`, r)
+		default:
+			sb.WriteRune(r)
+		}
+		i += size
+	}
+	return sb.String()
+}
@@ -0,0 +1,52 @@
+package purse
+
+import (
+	"fmt"
+	"strings"
+)
+
+var fakeWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit",
+	"sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore",
+	"magna", "aliqua", "enim", "ad", "minim", "veniam", "quis", "nostrud",
+	"exercitation", "ullamco", "laboris", "nisi", "aliquip", "ex", "ea", "commodo",
+	"consequat", "duis", "aute", "irure", "in", "reprehenderit", "voluptate",
+	"velit", "esse", "cillum", "fugiat", "nulla", "pariatur", "excepteur", "sint",
+	"occaecat", "cupidatat", "non", "proident", "sunt", "culpa", "qui", "officia",
+	"deserunt", "mollit", "anim", "id", "est", "laborum",
+}
+
+var fakeTLDs = []string{"com", "net", "org", "io", "dev", "app"}
+
+// RandHexColor generates a random CSS hex color such as "#3fa2c9".
+func RandHexColor() string {
+	return fmt.Sprintf("#%06x", randIntn(1<<24))
+}
+
+// RandIPv4 generates a random dotted IPv4 address.
+func RandIPv4() string {
+	return fmt.Sprintf("%d.%d.%d.%d", randIntn(256), randIntn(256), randIntn(256), randIntn(256))
+}
+
+// RandMAC generates a random locally administered unicast MAC address.
+func RandMAC() string {
+	octets := make([]string, 6)
+	for i := range octets {
+		b := randIntn(256)
+		if i == 0 {
+			b = b&0xfc | 0x02
+		}
+		octets[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(octets, ":")
+}
+
+// RandDomain generates a random domain name such as "tempor-magna.io".
+func RandDomain() string {
+	return fakeWords[randIntn(len(fakeWords))] + "-" + fakeWords[randIntn(len(fakeWords))] + "." + fakeTLDs[randIntn(len(fakeTLDs))]
+}
+
+// RandSentence generates a capitalized sentence of random words ending in a period.
+func RandSentence(words int) string {
+	return defaultRand.Sentence(words)
+}
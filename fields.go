@@ -0,0 +1,51 @@
+package purse
+
+import (
+	"strings"
+)
+
+// fieldAt returns fields[n], counting back from the end for negative n.
+func fieldAt(fields []string, n int) (string, bool) {
+	if n < 0 {
+		n += len(fields)
+	}
+	if n < 0 || n >= len(fields) {
+		return "", false
+	}
+	return fields[n], true
+}
+
+// Field returns the nth whitespace-separated field of a line, counting from 0.
+// Negative values count back from the last field. It returns "" when out of range.
+func Field(s string, n int) string {
+	field, _ := fieldAt(strings.Fields(s), n)
+	return field
+}
+
+// Fields2D splits a string into lines and each line into whitespace-separated fields.
+func Fields2D(s string) [][]string {
+	lines := MakeLines(s)
+	out := make([][]string, len(lines))
+	for i, line := range lines {
+		out[i] = strings.Fields(line)
+	}
+	return out
+}
+
+// SelectColumns keeps the given whitespace-separated columns of every line, joined by
+// single spaces. Columns count from 0, negative values count back from the last
+// column, and columns missing from a line are skipped.
+func SelectColumns(s string, cols ...int) string {
+	rows := Fields2D(s)
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		picked := make([]string, 0, len(cols))
+		for _, col := range cols {
+			if field, ok := fieldAt(row, col); ok {
+				picked = append(picked, field)
+			}
+		}
+		lines[i] = strings.Join(picked, " ")
+	}
+	return JoinLines(lines)
+}
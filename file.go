@@ -0,0 +1,113 @@
+package purse
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadFileLines reads a file and splits it into lines. A single final newline does not
+// produce an empty last line.
+func ReadFileLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return []string{}, nil
+	}
+	return MakeLines(StripFinalNewline(string(data))), nil
+}
+
+// WriteFileLines atomically writes lines to a file, ending each with a newline.
+func WriteFileLines(path string, lines []string) error {
+	content := ""
+	if len(lines) > 0 {
+		content = JoinLines(lines) + "\n"
+	}
+	return writeFileAtomic(path, content, 0o644)
+}
+
+// TransformFile rewrites a file with the result of fn applied to its contents.
+// The new contents are written to a temporary file that replaces the original in one
+// step, so readers never see a partially written file. The file's mode is kept.
+func TransformFile(path string, fn func(string) string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, fn(string(data)), info.Mode().Perm())
+}
+
+// writeFileAtomic writes content to a temporary file beside path and renames it into place.
+func writeFileAtomic(path, content string, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Clean up the temporary file if anything fails before the rename
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// PathExists reports whether anything exists at path.
+func PathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// IsFile reports whether path exists and is a regular file.
+func IsFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// IsDir reports whether path exists and is a directory.
+func IsDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// LooksLikePath reports whether a string is shaped like a file path, without touching
+// the filesystem. It accepts strings with a path separator, a leading "~" or ".", a
+// Windows drive letter, or a file extension, and rejects URLs and strings containing
+// control characters or whitespace-only content.
+func LooksLikePath(s string) bool {
+	if strings.TrimSpace(s) == "" || strings.Contains(s, "://") {
+		return false
+	}
+	if strings.IndexFunc(s, func(r rune) bool { return r < 0x20 || r == 0x7F }) != -1 {
+		return false
+	}
+	if strings.ContainsAny(s, `/\`) || strings.HasPrefix(s, "~") || strings.HasPrefix(s, ".") {
+		return true
+	}
+	if len(s) >= 2 && s[1] == ':' && (s[0] >= 'a' && s[0] <= 'z' || s[0] >= 'A' && s[0] <= 'Z') {
+		return true
+	}
+	ext := strings.TrimPrefix(filepath.Ext(s), ".")
+	if ext == "" || len(ext) > 10 || strings.Contains(s, " ") {
+		return false
+	}
+	return strings.IndexFunc(ext, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) == -1
+}
@@ -0,0 +1,120 @@
+package purse
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// equalFoldRune reports whether two runes are equal under Unicode simple case folding.
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFoldAt returns the byte length of the text at the start of s that matches sub
+// under case folding, or -1 if it does not match.
+func matchFoldAt(s, sub string) int {
+	i := 0
+	for _, want := range sub {
+		if i >= len(s) {
+			return -1
+		}
+		got, size := utf8.DecodeRuneInString(s[i:])
+		if !equalFoldRune(got, want) {
+			return -1
+		}
+		i += size
+	}
+	return i
+}
+
+// indexFold returns the byte index and length of the first case-insensitive match of sub in s.
+func indexFold(s, sub string) (int, int) {
+	if sub == "" {
+		return 0, 0
+	}
+	for i := range s {
+		if n := matchFoldAt(s[i:], sub); n != -1 {
+			return i, n
+		}
+	}
+	return -1, 0
+}
+
+// IndexFold returns the byte index of the first case-insensitive occurrence of sub in s, or -1.
+func IndexFold(s, sub string) int {
+	i, _ := indexFold(s, sub)
+	return i
+}
+
+// ContainsFold reports whether sub appears in s, ignoring case.
+func ContainsFold(s, sub string) bool {
+	return IndexFold(s, sub) != -1
+}
+
+// ReplaceAllFold replaces every case-insensitive occurrence of old in s with new,
+// leaving the casing of the rest of s untouched.
+func ReplaceAllFold(s, old, new string) string {
+	if old == "" {
+		return s
+	}
+	var sb strings.Builder
+	for {
+		i, n := indexFold(s, old)
+		if i == -1 {
+			sb.WriteString(s)
+			return sb.String()
+		}
+		sb.WriteString(s[:i])
+		sb.WriteString(new)
+		s = s[i+n:]
+	}
+}
+
+// SliceContainsFold checks if a slice contains an item, ignoring case.
+func SliceContainsFold(slice []string, item string) bool {
+	for _, s := range slice {
+		if strings.EqualFold(s, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// foldKey maps every rune of s to a canonical member of its case folding orbit,
+// so strings that are equal under folding share a key.
+func foldKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		least := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < least {
+				least = f
+			}
+		}
+		return least
+	}, s)
+}
+
+// RemoveDuplicatesFold removes items that duplicate an earlier item under case folding,
+// keeping the first occurrence as written.
+func RemoveDuplicatesFold(slice []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, item := range slice {
+		key := foldKey(item)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, item)
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package purse
+
+import (
+	"strings"
+)
+
+// Levenshtein returns the number of single-rune insertions, deletions and
+// substitutions needed to turn a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Similarity returns how alike two strings are, from 0 (nothing shared) to 1 (identical).
+func Similarity(a, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(longest)
+}
+
+// FindBestLineMatch locates the block of lines in haystack that most resembles needleBlock.
+// Lines are compared with surrounding whitespace ignored, and the score is the average
+// Similarity of the lines in the block. ok is false when the best score falls below
+// 1 - tolerance.
+func FindBestLineMatch(haystack string, needleBlock string, tolerance float64) (lineIndex int, score float64, ok bool) {
+	hay := MakeLines(haystack)
+	needle := MakeLines(needleBlock)
+	if len(needle) > len(hay) {
+		return -1, 0, false
+	}
+	lineIndex = -1
+	for start := 0; start+len(needle) <= len(hay); start++ {
+		total := 0.0
+		for i, line := range needle {
+			total += Similarity(strings.TrimSpace(hay[start+i]), strings.TrimSpace(line))
+		}
+		if avg := total / float64(len(needle)); avg > score || lineIndex == -1 {
+			lineIndex, score = start, avg
+		}
+	}
+	if score < 1-tolerance {
+		return -1, score, false
+	}
+	return lineIndex, score, true
+}
+
+// GroupSimilar clusters lines whose Similarity to a group's first line is at least
+// threshold. Each line joins the most similar group, or starts a new one when none is
+// close enough. Groups and the lines within them keep their input order.
+func GroupSimilar(lines []string, threshold float64) [][]string {
+	var groups [][]string
+	for _, line := range lines {
+		best, bestScore := -1, 0.0
+		for g, group := range groups {
+			score := Similarity(group[0], line)
+			if score >= threshold && (best == -1 || score > bestScore) {
+				best, bestScore = g, score
+			}
+		}
+		if best == -1 {
+			groups = append(groups, []string{line})
+			continue
+		}
+		groups[best] = append(groups[best], line)
+	}
+	return groups
+}
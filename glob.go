@@ -0,0 +1,101 @@
+package purse
+
+// MatchGlob reports whether s matches a wildcard pattern. A '*' matches any run of
+// characters, '?' matches a single character, "[abc]" and "[a-z]" match a character
+// class ("[!a-z]" or "[^a-z]" negates it), and '\' escapes the next character.
+// Unlike path.Match, '*' also matches '/'. A malformed class never matches.
+func MatchGlob(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+	pi, si := 0, 0
+	starP, starS := -1, 0
+	for si < len(str) {
+		if pi < len(p) {
+			switch p[pi] {
+			case '*':
+				starP, starS = pi, si
+				pi++
+				continue
+			case '?':
+				pi++
+				si++
+				continue
+			case '[':
+				matched, next, ok := matchClass(p, pi, str[si])
+				if !ok {
+					return false
+				}
+				if matched {
+					pi = next
+					si++
+					continue
+				}
+			case '\\':
+				if pi+1 < len(p) && p[pi+1] == str[si] {
+					pi += 2
+					si++
+					continue
+				}
+			default:
+				if p[pi] == str[si] {
+					pi++
+					si++
+					continue
+				}
+			}
+		}
+		if starP == -1 {
+			return false
+		}
+		starS++
+		pi, si = starP+1, starS
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// matchClass matches r against the class starting at p[start], returning whether it
+// matched, the index just past the class, and false if the class is malformed.
+func matchClass(p []rune, start int, r rune) (bool, int, bool) {
+	i := start + 1
+	negate := false
+	if i < len(p) && (p[i] == '!' || p[i] == '^') {
+		negate = true
+		i++
+	}
+	matched := false
+	first := true
+	for i < len(p) && (p[i] != ']' || first) {
+		first = false
+		lo := p[i]
+		if lo == '\\' && i+1 < len(p) {
+			i++
+			lo = p[i]
+		}
+		hi := lo
+		if i+2 < len(p) && p[i+1] == '-' && p[i+2] != ']' {
+			hi = p[i+2]
+			i += 2
+		}
+		if lo <= r && r <= hi {
+			matched = true
+		}
+		i++
+	}
+	if i >= len(p) {
+		return false, 0, false
+	}
+	return matched != negate, i + 1, true
+}
+
+// FilterGlob returns the items of a slice that match a wildcard pattern.
+func FilterGlob(slice []string, pattern string) []string {
+	var out []string
+	for _, item := range slice {
+		if MatchGlob(pattern, item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,40 @@
+package purse
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/fnv"
+)
+
+// Hash returns the 64-bit FNV-1a hash of s. It is fast and stable across runs, but
+// not suitable where collisions must be hard to produce.
+func Hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// ShortHash returns the first n hex characters of the SHA-256 of s. n is clamped to
+// the 64 characters of a full digest, and a non-positive n returns the full digest.
+func ShortHash(s string, n int) string {
+	sum := sha256.Sum256([]byte(s))
+	full := hex.EncodeToString(sum[:])
+	if n <= 0 || n > len(full) {
+		return full
+	}
+	return full[:n]
+}
+
+// ContentFingerprint returns a SHA-256 hex digest identifying a list of lines. Each line
+// is length-prefixed before hashing, so ["ab", "c"] and ["a", "bc"] fingerprint differently.
+func ContentFingerprint(lines []string) string {
+	h := sha256.New()
+	var size [8]byte
+	for _, line := range lines {
+		binary.BigEndian.PutUint64(size[:], uint64(len(line)))
+		h.Write(size[:])
+		h.Write([]byte(line))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,110 @@
+package purse
+
+import (
+	"strings"
+)
+
+// isTagStart reports whether s begins an HTML tag, comment or declaration.
+func isTagStart(s string) bool {
+	if len(s) < 2 || s[0] != '<' {
+		return false
+	}
+	c := s[1]
+	return c == '/' || c == '!' || c == '?' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// SplitTags splits an HTML-ish string into tag and text tokens. Tags, comments and
+// declarations are single tokens, quoted attribute values may contain '>', and joining
+// the tokens reproduces the input. A '<' that does not start a tag stays in the text.
+func SplitTags(s string) []string {
+	var tokens []string
+	textStart := 0
+	i := 0
+	for i < len(s) {
+		if !isTagStart(s[i:]) {
+			i++
+			continue
+		}
+		if i > textStart {
+			tokens = append(tokens, s[textStart:i])
+		}
+		end := tagEnd(s, i)
+		tokens = append(tokens, s[i:end])
+		i, textStart = end, end
+	}
+	if textStart < len(s) {
+		tokens = append(tokens, s[textStart:])
+	}
+	return tokens
+}
+
+// tagEnd returns the offset just past the tag starting at i, or len(s) if it never closes.
+func tagEnd(s string, i int) int {
+	if strings.HasPrefix(s[i:], "<!--") {
+		if end := strings.Index(s[i+4:], "-->"); end != -1 {
+			return i + 4 + end + 3
+		}
+		return len(s)
+	}
+	var quote byte
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case quote != 0:
+			if s[j] == quote {
+				quote = 0
+			}
+		case s[j] == '"' || s[j] == '\'':
+			quote = s[j]
+		case s[j] == '>':
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+// GetAttr returns the value of an attribute in a tag such as `<a href="/x">`.
+// Attribute names match case-insensitively, and an attribute without a value
+// returns an empty string and true.
+func GetAttr(tag, name string) (string, bool) {
+	body := strings.TrimSuffix(strings.TrimSuffix(strings.TrimPrefix(tag, "<"), ">"), "/")
+	// Skip the tag name
+	i := strings.IndexAny(body, " \t\r\n")
+	if i == -1 {
+		return "", false
+	}
+	rest := body[i:]
+	for {
+		rest = strings.TrimLeft(rest, " \t\r\n/")
+		if rest == "" {
+			return "", false
+		}
+		end := strings.IndexAny(rest, " \t\r\n=/")
+		if end == -1 {
+			end = len(rest)
+		}
+		attr := rest[:end]
+		rest = strings.TrimLeft(rest[end:], " \t\r\n")
+		value := ""
+		hasValue := strings.HasPrefix(rest, "=")
+		if hasValue {
+			rest = strings.TrimLeft(rest[1:], " \t\r\n")
+			if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+				endQuote := strings.IndexByte(rest[1:], rest[0])
+				if endQuote == -1 {
+					value, rest = rest[1:], ""
+				} else {
+					value, rest = rest[1:endQuote+1], rest[endQuote+2:]
+				}
+			} else {
+				stop := strings.IndexAny(rest, " \t\r\n")
+				if stop == -1 {
+					stop = len(rest)
+				}
+				value, rest = rest[:stop], rest[stop:]
+			}
+		}
+		if strings.EqualFold(attr, name) {
+			return value, true
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package purse
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var irregularPlurals = map[string]string{
+	"child":  "children",
+	"person": "people",
+	"man":    "men",
+	"woman":  "women",
+	"mouse":  "mice",
+	"foot":   "feet",
+	"tooth":  "teeth",
+	"goose":  "geese",
+	"leaf":   "leaves",
+	"life":   "lives",
+	"knife":  "knives",
+	"wife":   "wives",
+	"half":   "halves",
+	"wolf":   "wolves",
+	"index":  "indices",
+	"datum":  "data",
+}
+
+// Pluralize returns word in the form that goes with a count of n, so
+// fmt.Sprintf("%d %s changed", n, Pluralize("file", n)) reads correctly.
+func Pluralize(word string, n int) string {
+	if n == 1 || n == -1 || word == "" {
+		return word
+	}
+	lower := strings.ToLower(word)
+	if plural, ok := irregularPlurals[lower]; ok {
+		if word[:1] != lower[:1] {
+			plural = strings.ToUpper(plural[:1]) + plural[1:]
+		}
+		return plural
+	}
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		return word + "es"
+	case len(lower) > 1 && strings.HasSuffix(lower, "y") && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		return word[:len(word)-1] + "ies"
+	default:
+		return word + "s"
+	}
+}
+
+// Ordinal returns n with its English ordinal suffix, such as "1st", "12th" or "23rd".
+func Ordinal(n int) string {
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+	suffix := "th"
+	if abs%100 < 11 || abs%100 > 13 {
+		switch abs % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// HumanizeBytes formats a byte count with binary units, such as "512 B" or "1.5 MiB".
+func HumanizeBytes(n int64) string {
+	const unit = 1024
+	// The magnitude is unsigned so math.MinInt64 negates without overflowing
+	sign, mag := "", uint64(n)
+	if n < 0 {
+		sign, mag = "-", -mag
+	}
+	if mag < unit {
+		return fmt.Sprintf("%s%d B", sign, mag)
+	}
+	value := float64(mag)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	i := -1
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	// Rounding can carry into the next unit, as 1023.96 KiB does
+	value = math.Round(value*10) / 10
+	if value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	formatted := strings.TrimSuffix(strconv.FormatFloat(value, 'f', 1, 64), ".0")
+	return sign + formatted + " " + suffixes[i]
+}
+
+// HumanizeDuration formats a duration using its two largest units, such as "2h 5m",
+// "3m 20s" or "250ms".
+func HumanizeDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	if d < time.Second {
+		switch {
+		case d >= time.Millisecond:
+			return sign + strconv.FormatInt(int64(d/time.Millisecond), 10) + "ms"
+		case d >= time.Microsecond:
+			return sign + strconv.FormatInt(int64(d/time.Microsecond), 10) + "µs"
+		default:
+			return sign + strconv.FormatInt(int64(d), 10) + "ns"
+		}
+	}
+	units := []struct {
+		size time.Duration
+		name string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+	var parts []string
+	for i, u := range units {
+		if d < u.size {
+			continue
+		}
+		parts = append(parts, strconv.FormatInt(int64(d/u.size), 10)+u.name)
+		if i+1 < len(units) {
+			if rest := d % u.size / units[i+1].size; rest > 0 {
+				parts = append(parts, strconv.FormatInt(int64(rest), 10)+units[i+1].name)
+			}
+		}
+		break
+	}
+	return sign + strings.Join(parts, " ")
+}
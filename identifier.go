@@ -0,0 +1,89 @@
+package purse
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SplitIdentifier breaks an identifier into words at camelCase and PascalCase humps,
+// acronym boundaries, digit runs and any separator that is not a letter or digit, so
+// "parseHTTP2Frames" becomes ["parse", "HTTP", "2", "Frames"] and "user_id-list"
+// becomes ["user", "id", "list"].
+func SplitIdentifier(s string) []string {
+	var words []string
+	runes := []rune(s)
+	start := -1
+	flush := func(end int) {
+		if start != -1 && end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = -1
+	}
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush(i)
+			continue
+		}
+		if start == -1 {
+			start = i
+			continue
+		}
+		prev := runes[i-1]
+		switch {
+		case unicode.IsDigit(r) != unicode.IsDigit(prev):
+			flush(i)
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			flush(i)
+		case unicode.IsUpper(prev) && unicode.IsUpper(r) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// The last capital of an acronym starts the next word, as in "HTTPServer"
+			flush(i)
+		}
+		if start == -1 {
+			start = i
+		}
+	}
+	flush(len(runes))
+	return words
+}
+
+// capitalize upper-cases the first rune of a word and lower-cases the rest.
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) == 0 {
+		return ""
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// ToCamelCase converts an identifier to camelCase, such as "userId".
+func ToCamelCase(s string) string {
+	words := SplitIdentifier(s)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+			continue
+		}
+		words[i] = capitalize(word)
+	}
+	return strings.Join(words, "")
+}
+
+// ToPascalCase converts an identifier to PascalCase, such as "UserId".
+func ToPascalCase(s string) string {
+	words := SplitIdentifier(s)
+	for i, word := range words {
+		words[i] = capitalize(word)
+	}
+	return strings.Join(words, "")
+}
+
+// ToSnakeCase converts an identifier to snake_case, such as "user_id".
+func ToSnakeCase(s string) string {
+	return strings.ToLower(strings.Join(SplitIdentifier(s), "_"))
+}
+
+// ToKebabCase converts an identifier to kebab-case, such as "user-id".
+func ToKebabCase(s string) string {
+	return strings.ToLower(strings.Join(SplitIdentifier(s), "-"))
+}
@@ -0,0 +1,125 @@
+package purse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Indent adds level copies of unit to the start of every non-blank line.
+func Indent(s string, level int, unit string) string {
+	if level <= 0 {
+		return s
+	}
+	prefix := strings.Repeat(unit, level)
+	return MapLines(s, func(line string, _ int) string {
+		if strings.TrimSpace(line) == "" {
+			return line
+		}
+		return prefix + line
+	})
+}
+
+// Outdent removes up to level copies of unit from the start of every line.
+func Outdent(s string, level int, unit string) string {
+	if level <= 0 || unit == "" {
+		return s
+	}
+	return MapLines(s, func(line string, _ int) string {
+		for j := 0; j < level && strings.HasPrefix(line, unit); j++ {
+			line = line[len(unit):]
+		}
+		return line
+	})
+}
+
+// DetectIndentUnit infers whether a document is indented with tabs or a number of spaces.
+// It returns an empty string when no line is indented.
+func DetectIndentUnit(s string) string {
+	tabs, spaced := 0, 0
+	unit := 0
+	for _, line := range MakeLines(s) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "\t") {
+			tabs++
+			continue
+		}
+		n := CountLeadingSpaces(line)
+		if n == 0 {
+			continue
+		}
+		spaced++
+		unit = gcd(unit, n)
+	}
+	if tabs == 0 && spaced == 0 {
+		return ""
+	}
+	if tabs >= spaced {
+		return "\t"
+	}
+	return strings.Repeat(" ", unit)
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// commonIndent returns the longest run of leading spaces and tabs shared by every
+// non-blank line of s.
+func commonIndent(s string) string {
+	common, found := "", false
+	eachLine(s, func(_ int, line string) {
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if !found {
+			common, found = indent, true
+			return
+		}
+		i := 0
+		for i < len(common) && i < len(indent) && common[i] == indent[i] {
+			i++
+		}
+		common = common[:i]
+	})
+	return common
+}
+
+// Dedent removes the indentation shared by every non-blank line, so a block keeps its
+// relative indentation but starts at column zero. Whitespace-only lines become empty.
+func Dedent(s string) string {
+	indent := commonIndent(s)
+	return MapLines(s, func(line string, _ int) string {
+		if strings.TrimSpace(line) == "" {
+			return ""
+		}
+		return line[len(indent):]
+	})
+}
+
+// Heredoc tidies an indented raw string literal: a blank first line is dropped and the
+// common indentation is removed, so expected output in tests can be written as
+//
+//	want := purse.Heredoc(`
+//		first
+//		  second
+//	`)
+//
+// which yields "first\n  second\n".
+func Heredoc(s string) string {
+	if i := strings.IndexByte(s, '\n'); i != -1 && strings.TrimSpace(s[:i]) == "" {
+		s = s[i+1:]
+	}
+	return Dedent(s)
+}
+
+// Heredocf is Heredoc followed by fmt.Sprintf. The literal is tidied before formatting,
+// so multi-line arguments are inserted as given.
+func Heredocf(format string, args ...any) string {
+	return fmt.Sprintf(Heredoc(format), args...)
+}
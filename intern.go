@@ -0,0 +1,79 @@
+package purse
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+	"sync"
+)
+
+// LinePool interns strings so identical values share one copy in memory.
+// It is safe for concurrent use.
+type LinePool struct {
+	mu      sync.Mutex
+	strings map[string]string
+}
+
+// NewLinePool creates an empty LinePool.
+func NewLinePool() *LinePool {
+	return &LinePool{strings: make(map[string]string)}
+}
+
+// Intern returns the pooled copy of s, adding it to the pool if needed.
+// New values are cloned so they do not keep a larger source string alive.
+func (p *LinePool) Intern(s string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pooled, ok := p.strings[s]; ok {
+		return pooled
+	}
+	pooled := strings.Clone(s)
+	p.strings[pooled] = pooled
+	return pooled
+}
+
+// Len returns the number of distinct strings in the pool.
+func (p *LinePool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.strings)
+}
+
+// MakeLinesInterned splits a string into lines, interning each line in pool.
+// A nil pool uses a fresh pool for this call only.
+func MakeLinesInterned(s string, pool *LinePool) []string {
+	if pool == nil {
+		pool = NewLinePool()
+	}
+	lines := MakeLines(s)
+	for i, line := range lines {
+		lines[i] = pool.Intern(line)
+	}
+	return lines
+}
+
+// ReadDelimitedInterned reads sep-delimited records from r, CSV quoting rules included,
+// interning every field so repeated values share memory. Records may have differing
+// numbers of fields.
+func ReadDelimitedInterned(r io.Reader, sep rune) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = sep
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	pool := NewLinePool()
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make([]string, len(record))
+		for i, field := range record {
+			row[i] = pool.Intern(field)
+		}
+		records = append(records, row)
+	}
+}
@@ -0,0 +1,81 @@
+package purse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+// JSONOption configures PrettyJSON and CompactJSON.
+type JSONOption func(*jsonConfig)
+
+type jsonConfig struct {
+	sortKeys bool
+}
+
+// SortKeys orders object keys alphabetically, so equal documents always format the
+// same way. Without it, keys keep the order they were written in.
+func SortKeys() JSONOption {
+	return func(c *jsonConfig) {
+		c.sortKeys = true
+	}
+}
+
+// PrettyJSON formats a JSON document with one element per line, nested with indent.
+func PrettyJSON(s string, indent string, opts ...JSONOption) (string, error) {
+	data, err := normalizeJSON(s, opts)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", indent); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// CompactJSON formats a JSON document with all insignificant whitespace removed.
+func CompactJSON(s string, opts ...JSONOption) (string, error) {
+	data, err := normalizeJSON(s, opts)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// normalizeJSON validates s and applies the options, returning the document to format.
+func normalizeJSON(s string, opts []JSONOption) ([]byte, error) {
+	var cfg jsonConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if !cfg.sortKeys {
+		return []byte(s), nil
+	}
+	// Decoding into maps and encoding again sorts the keys; UseNumber keeps numbers
+	// exactly as written instead of rounding them through float64
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the top-level JSON value")
+	}
+	// An Encoder is used instead of json.Marshal so "<tag>" is not escaped, but it ends
+	// the document with a newline that the unsorted path never has
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
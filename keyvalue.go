@@ -0,0 +1,113 @@
+package purse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DuplicatePolicy decides what happens when a key appears more than once.
+type DuplicatePolicy int
+
+const (
+	DuplicateLast  DuplicatePolicy = iota // later values overwrite earlier ones
+	DuplicateFirst                        // the first value is kept
+	DuplicateError                        // a repeated key is an error
+)
+
+// KVOption configures ParseKeyValues and ParseSections.
+type KVOption func(*kvConfig)
+
+type kvConfig struct {
+	duplicates DuplicatePolicy
+}
+
+// OnDuplicate sets how repeated keys are handled.
+func OnDuplicate(policy DuplicatePolicy) KVOption {
+	return func(c *kvConfig) {
+		c.duplicates = policy
+	}
+}
+
+// ParseKeyValues parses "key <sep> value" lines into a map. Blank lines and lines
+// starting with '#' or ';' are skipped, keys and values are trimmed, and values
+// lose any wrapping quotes.
+func ParseKeyValues(s string, sep string, opts ...KVOption) (map[string]string, error) {
+	cfg := newKVConfig(opts)
+	out := make(map[string]string)
+	for i, line := range MakeLines(s) {
+		if isKVSkippable(line) {
+			continue
+		}
+		if err := cfg.put(out, line, sep, i+1); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// ParseSections parses an INI-style document of "[section]" headers and "key = value"
+// lines. Keys that appear before any header are placed in the "" section.
+func ParseSections(s string, opts ...KVOption) (map[string]map[string]string, error) {
+	cfg := newKVConfig(opts)
+	out := map[string]map[string]string{}
+	section := ""
+	for i, line := range MakeLines(s) {
+		if isKVSkippable(line) {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			if !strings.HasSuffix(trimmed, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header %q", i+1, trimmed)
+			}
+			section = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if _, ok := out[section]; !ok {
+				out[section] = make(map[string]string)
+			}
+			continue
+		}
+		if out[section] == nil {
+			out[section] = make(map[string]string)
+		}
+		if err := cfg.put(out[section], line, "=", i+1); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func newKVConfig(opts []KVOption) kvConfig {
+	var cfg kvConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+func isKVSkippable(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";")
+}
+
+// put parses a single key/value line into m according to the duplicate policy.
+func (c kvConfig) put(m map[string]string, line, sep string, lineNum int) error {
+	key, value, ok := strings.Cut(line, sep)
+	if !ok {
+		return fmt.Errorf("line %d: missing separator %q", lineNum, sep)
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return fmt.Errorf("line %d: empty key", lineNum)
+	}
+	value = RemoveWrappingQuotes(strings.TrimSpace(value))
+	if _, exists := m[key]; exists {
+		switch c.duplicates {
+		case DuplicateFirst:
+			return nil
+		case DuplicateError:
+			return fmt.Errorf("line %d: duplicate key %q", lineNum, key)
+		}
+	}
+	m[key] = value
+	return nil
+}
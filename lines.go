@@ -0,0 +1,241 @@
+package purse
+
+import (
+	"strconv"
+	"strings"
+)
+
+// lineIndex resolves a possibly negative line index against n lines, clamping it to [0, n].
+func lineIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > n {
+		return n
+	}
+	return i
+}
+
+// GetLines returns lines from (inclusive) to to (exclusive).
+// Negative indexes count back from the last line.
+func GetLines(s string, from, to int) string {
+	lines := MakeLines(s)
+	from, to = lineIndex(from, len(lines)), lineIndex(to, len(lines))
+	if from >= to {
+		return ""
+	}
+	return JoinLines(lines[from:to])
+}
+
+// ReplaceLines replaces lines from (inclusive) to to (exclusive) with replacement.
+// Negative indexes count back from the last line.
+func ReplaceLines(s string, from, to int, replacement string) string {
+	lines := MakeLines(s)
+	from, to = lineIndex(from, len(lines)), lineIndex(to, len(lines))
+	if to < from {
+		to = from
+	}
+	out := make([]string, 0, len(lines))
+	out = append(out, lines[:from]...)
+	out = append(out, MakeLines(replacement)...)
+	out = append(out, lines[to:]...)
+	return JoinLines(out)
+}
+
+// InsertLineAt inserts a line before line i. Negative indexes count back from the last line.
+func InsertLineAt(s string, i int, line string) string {
+	lines := MakeLines(s)
+	i = lineIndex(i, len(lines))
+	return ReplaceLines(s, i, i, line)
+}
+
+// RemoveLineAt removes line i. Negative indexes count back from the last line.
+func RemoveLineAt(s string, i int) string {
+	lines := MakeLines(s)
+	if i < 0 {
+		i += len(lines)
+	}
+	if i < 0 || i >= len(lines) {
+		return s
+	}
+	return JoinLines(append(lines[:i], lines[i+1:]...))
+}
+
+// NumberOpts configures NumberLines.
+type NumberOpts struct {
+	Start int    // number of the first line, defaults to 1
+	Sep   string // placed between the number and the line, defaults to " | "
+}
+
+// NumberLines prefixes each line with its right-aligned line number.
+func NumberLines(s string, opts NumberOpts) string {
+	start := opts.Start
+	if start == 0 {
+		start = 1
+	}
+	sep := opts.Sep
+	if sep == "" {
+		sep = " | "
+	}
+	lines := MakeLines(s)
+	width := len(strconv.Itoa(start + len(lines) - 1))
+	for i, line := range lines {
+		lines[i] = PadToWidth(strconv.Itoa(start+i), width, AlignRight) + sep + line
+	}
+	return JoinLines(lines)
+}
+
+// HighlightLine prefixes line n (counting from 1) with marker and indents every
+// other line by the marker's width so the text stays aligned.
+func HighlightLine(s string, n int, marker string) string {
+	blank := strings.Repeat(" ", VisualWidth(marker))
+	lines := MakeLines(s)
+	for i, line := range lines {
+		if i == n-1 {
+			lines[i] = marker + line
+			continue
+		}
+		lines[i] = blank + line
+	}
+	return JoinLines(lines)
+}
+
+// SplitDocuments splits a stream of documents on lines that consist solely of separator,
+// trimming blank lines from the start and end of each document. Empty documents are dropped.
+func SplitDocuments(s string, separator string) []string {
+	var docs []string
+	var current []string
+	flush := func() {
+		doc := trimBlankLines(current)
+		if len(doc) > 0 {
+			docs = append(docs, JoinLines(doc))
+		}
+		current = nil
+	}
+	for _, line := range MakeLines(s) {
+		if strings.TrimRight(line, " \t\r") == separator {
+			flush()
+			continue
+		}
+		current = append(current, line)
+	}
+	flush()
+	return docs
+}
+
+// trimBlankLines drops blank lines from both ends of a slice of lines.
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// WrapBlock places header and footer on their own lines around s.
+func WrapBlock(s, header, footer string) string {
+	return JoinLines([]string{header, s, footer})
+}
+
+// UnwrapBlock returns the lines between the first line equal to header and the next line
+// equal to footer, ignoring surrounding whitespace on the marker lines.
+func UnwrapBlock(s, header, footer string) (string, bool) {
+	lines := MakeLines(s)
+	start := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if start == -1 {
+			if trimmed == strings.TrimSpace(header) {
+				start = i + 1
+			}
+			continue
+		}
+		if trimmed == strings.TrimSpace(footer) {
+			return JoinLines(lines[start:i]), true
+		}
+	}
+	return "", false
+}
+
+// eachLine calls fn with every line of s, exactly as MakeLines would split it, without
+// allocating a slice of lines.
+func eachLine(s string, fn func(i int, line string)) {
+	for i := 0; ; i++ {
+		j := strings.IndexByte(s, '\n')
+		if j == -1 {
+			fn(i, s)
+			return
+		}
+		fn(i, s[:j])
+		s = s[j+1:]
+	}
+}
+
+// MapLines replaces every line of a string with the result of fn, which receives the line
+// and its index. The result is built in a single pass, so no intermediate slice of lines
+// is allocated.
+func MapLines(s string, fn func(line string, i int) string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	eachLine(s, func(i int, line string) {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(fn(line, i))
+	})
+	return sb.String()
+}
+
+// FilterLines keeps only the lines of a string for which keep returns true. Like
+// MapLines, it builds the result in a single pass.
+func FilterLines(s string, keep func(line string, i int) bool) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+	first := true
+	eachLine(s, func(i int, line string) {
+		if !keep(line, i) {
+			return
+		}
+		if !first {
+			sb.WriteByte('\n')
+		}
+		first = false
+		sb.WriteString(line)
+	})
+	return sb.String()
+}
+
+// AnyLineHasPrefix reports whether at least one line of s starts with prefix.
+func AnyLineHasPrefix(s, prefix string) bool {
+	if strings.HasPrefix(s, prefix) {
+		return true
+	}
+	return strings.Contains(s, "\n"+prefix)
+}
+
+// AllLinesHavePrefix reports whether every line of s starts with prefix.
+func AllLinesHavePrefix(s, prefix string) bool {
+	for _, line := range MakeLines(s) {
+		if !strings.HasPrefix(line, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// LinesWithPrefix returns the 0-based indexes of the lines of s that start with prefix,
+// ready to pass to GetLines or RemoveLineAt.
+func LinesWithPrefix(s, prefix string) []int {
+	var out []int
+	for i, line := range MakeLines(s) {
+		if strings.HasPrefix(line, prefix) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
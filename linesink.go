@@ -0,0 +1,50 @@
+package purse
+
+import (
+	"bytes"
+	"sync"
+)
+
+// LineSink is an io.Writer that splits whatever is written to it into lines and passes
+// each complete line, without its newline, to a callback. Partial lines are buffered
+// until their newline arrives or Flush is called. It is safe for concurrent use, so one
+// sink can be attached to both the stdout and stderr of a command.
+type LineSink struct {
+	mu     sync.Mutex
+	buf    []byte
+	onLine func(line string)
+}
+
+// NewLineSink creates a LineSink that calls onLine for every complete line.
+func NewLineSink(onLine func(line string)) *LineSink {
+	return &LineSink{onLine: onLine}
+}
+
+// Write buffers p and emits every line it completes. It always consumes all of p.
+func (ls *LineSink) Write(p []byte) (int, error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.buf = append(ls.buf, p...)
+	start := 0
+	for {
+		i := bytes.IndexByte(ls.buf[start:], '\n')
+		if i == -1 {
+			break
+		}
+		ls.onLine(string(ls.buf[start : start+i]))
+		start += i + 1
+	}
+	// Move the partial tail to the front so the buffer does not grow without bound
+	ls.buf = ls.buf[:copy(ls.buf, ls.buf[start:])]
+	return len(p), nil
+}
+
+// Flush emits the buffered partial line, if any, as a final line.
+func (ls *LineSink) Flush() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if len(ls.buf) > 0 {
+		ls.onLine(string(ls.buf))
+		ls.buf = ls.buf[:0]
+	}
+}
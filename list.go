@@ -0,0 +1,56 @@
+package purse
+
+import (
+	"strings"
+)
+
+// CheckItem is a single entry of a Checklist.
+type CheckItem struct {
+	Text     string
+	Done     bool
+	Children []CheckItem
+}
+
+// BulletList renders items as a bulleted list. Leading tabs on an item nest it one
+// level deeper per tab, and continuation lines are aligned under the item's text.
+func BulletList(items []string, bullet string) string {
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		depth := CountLeadingTabs(item)
+		lines = append(lines, listEntry(strings.Repeat("  ", depth), bullet+" ", item[depth:]))
+	}
+	return JoinLines(lines)
+}
+
+// Checklist renders items as a task list using [x] and [ ] markers.
+// Children are nested two spaces deeper than their parent.
+func Checklist(items []CheckItem) string {
+	lines := make([]string, 0, len(items))
+	appendCheckItems(&lines, items, 0)
+	return JoinLines(lines)
+}
+
+func appendCheckItems(lines *[]string, items []CheckItem, depth int) {
+	for _, item := range items {
+		marker := "- [ ] "
+		if item.Done {
+			marker = "- [x] "
+		}
+		*lines = append(*lines, listEntry(strings.Repeat("  ", depth), marker, item.Text))
+		appendCheckItems(lines, item.Children, depth+1)
+	}
+}
+
+// listEntry renders a possibly multi-line entry with its marker, aligning continuation lines.
+func listEntry(indent, marker, text string) string {
+	hang := indent + strings.Repeat(" ", VisualWidth(marker))
+	lines := MakeLines(text)
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = indent + marker + line
+			continue
+		}
+		lines[i] = hang + line
+	}
+	return JoinLines(lines)
+}
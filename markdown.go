@@ -0,0 +1,133 @@
+package purse
+
+import (
+	"strings"
+)
+
+// SplitFrontmatter separates a "---" (YAML) or "+++" (TOML) delimited block at the top
+// of a document from the body that follows it. The delimiters are not included in
+// either result. ok is false, and body is the whole document, when there is no
+// complete frontmatter block.
+func SplitFrontmatter(s string) (frontmatter, body string, ok bool) {
+	doc := strings.TrimPrefix(s, "\uFEFF")
+	lines := MakeLines(doc)
+	delim := strings.TrimRight(lines[0], " \t\r")
+	if delim != "---" && delim != "+++" {
+		return "", s, false
+	}
+	offset := len(lines[0]) + 1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t\r") == delim {
+			frontmatter = strings.TrimSuffix(JoinLines(lines[1:i]), "\r")
+			body = ""
+			if end := offset + len(lines[i]) + 1; end < len(doc) {
+				body = doc[end:]
+			}
+			return frontmatter, body, true
+		}
+		offset += len(lines[i]) + 1
+	}
+	return "", s, false
+}
+
+// Fence is a fenced code block from a Markdown document.
+type Fence struct {
+	Lang string
+	Body string
+}
+
+// Heading is a Markdown heading with its 1-based line number.
+type Heading struct {
+	Level int
+	Text  string
+	Line  int
+}
+
+// openFence reports whether line opens a code fence, returning its indentation,
+// fence marker and info string.
+func openFence(line string) (indent, marker, info string, ok bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	indent = line[:len(line)-len(trimmed)]
+	for _, ch := range []string{"`", "~"} {
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, ch))
+		if n >= 3 {
+			info = strings.TrimSpace(trimmed[n:])
+			// Backtick fences may not carry backticks in their info string
+			if ch == "`" && strings.Contains(info, "`") {
+				return "", "", "", false
+			}
+			return indent, trimmed[:n], info, true
+		}
+	}
+	return "", "", "", false
+}
+
+// closesFence reports whether line closes a fence opened with marker.
+func closesFence(line, marker string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, marker) && strings.Trim(trimmed, marker[:1]) == ""
+}
+
+// ExtractCodeFences returns the fenced code blocks of a Markdown document in order.
+// Fences may be indented, as they are inside list items, and that indentation is
+// removed from the body. The language is the first word of the info string.
+// An unclosed fence runs to the end of the document.
+func ExtractCodeFences(s string) []Fence {
+	var fences []Fence
+	lines := MakeLines(s)
+	for i := 0; i < len(lines); i++ {
+		indent, marker, info, ok := openFence(lines[i])
+		if !ok {
+			continue
+		}
+		var body []string
+		for i++; i < len(lines) && !closesFence(lines[i], marker); i++ {
+			body = append(body, strings.TrimPrefix(lines[i], indent))
+		}
+		lang := ""
+		if words := strings.Fields(info); len(words) > 0 {
+			lang = words[0]
+		}
+		fences = append(fences, Fence{Lang: lang, Body: JoinLines(body)})
+	}
+	return fences
+}
+
+// ListHeadings returns the ATX ("## Title") and setext (underlined) headings of a
+// Markdown document, skipping anything inside code fences.
+func ListHeadings(s string) []Heading {
+	var headings []Heading
+	lines := MakeLines(s)
+	marker := ""
+	for i, line := range lines {
+		if marker != "" {
+			if closesFence(line, marker) {
+				marker = ""
+			}
+			continue
+		}
+		if _, m, _, ok := openFence(line); ok {
+			marker = m
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if level := len(trimmed) - len(strings.TrimLeft(trimmed, "#")); level >= 1 && level <= 6 {
+			rest := trimmed[level:]
+			if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+				text := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(rest), "#"))
+				headings = append(headings, Heading{Level: level, Text: text, Line: i + 1})
+				continue
+			}
+		}
+		if i+1 < len(lines) && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			under := strings.TrimSpace(lines[i+1])
+			switch {
+			case under != "" && strings.Trim(under, "=") == "":
+				headings = append(headings, Heading{Level: 1, Text: trimmed, Line: i + 1})
+			case len(under) >= 2 && strings.Trim(under, "-") == "" && (i == 0 || strings.TrimSpace(lines[i-1]) == ""):
+				headings = append(headings, Heading{Level: 2, Text: trimmed, Line: i + 1})
+			}
+		}
+	}
+	return headings
+}
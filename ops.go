@@ -0,0 +1,185 @@
+package purse
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Op is a serializable description of one transformation, so recipes can be read from
+// configuration, such as {"op": "PrefixLines", "prefix": "> "}. Name selects the
+// operation and the other fields are its arguments; each operation reads only the
+// fields it needs, and rejects a recipe that leaves a required one out.
+type Op struct {
+	Name     string `json:"op"`
+	Prefix   string `json:"prefix,omitempty"`
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
+	Unit     string `json:"unit,omitempty"`
+	Level    int    `json:"level,omitempty"`
+	Width    int    `json:"width,omitempty"`
+	Ellipsis string `json:"ellipsis,omitempty"`
+	Style    string `json:"style,omitempty"`
+}
+
+// OpFunc performs an operation on s using the arguments in op.
+type OpFunc func(s string, op Op) (string, error)
+
+var (
+	opsMu sync.RWMutex
+	ops   = map[string]OpFunc{
+		"PrefixLines": func(s string, op Op) (string, error) {
+			return PrefixLines(s, op.Prefix), nil
+		},
+		"RemoveEmptyLines": simpleOp(RemoveEmptyLines),
+		"RemoveTrailingEmptyLines": func(s string, _ Op) (string, error) {
+			return RemoveTrailingEmptyLines(s), nil
+		},
+		"TrimLeadingSpaces":     simpleOp(TrimLeadingSpaces),
+		"TrimTrailingSpaces":    simpleOp(TrimTrailingSpaces),
+		"Flatten":               simpleOp(Flatten),
+		"Dedent":                simpleOp(Dedent),
+		"NormalizeWhitespace":   simpleOp(NormalizeWhitespace),
+		"EnsureTrailingNewline": simpleOp(EnsureTrailingNewline),
+		"StripFinalNewline":     simpleOp(StripFinalNewline),
+		"Indent": func(s string, op Op) (string, error) {
+			if err := requireIndent(op); err != nil {
+				return "", err
+			}
+			return Indent(s, op.Level, op.Unit), nil
+		},
+		"Outdent": func(s string, op Op) (string, error) {
+			if err := requireIndent(op); err != nil {
+				return "", err
+			}
+			return Outdent(s, op.Level, op.Unit), nil
+		},
+		"ExpandTabs": func(s string, op Op) (string, error) {
+			if op.Width <= 0 {
+				return "", errMissingWidth
+			}
+			return ExpandTabs(s, op.Width), nil
+		},
+		"UnexpandTabs": func(s string, op Op) (string, error) {
+			if op.Width <= 0 {
+				return "", errMissingWidth
+			}
+			return UnexpandTabs(s, op.Width), nil
+		},
+		"Truncate": func(s string, op Op) (string, error) {
+			if op.Width <= 0 {
+				return "", errMissingWidth
+			}
+			return Truncate(s, op.Width, op.Ellipsis), nil
+		},
+		"NormalizeLineEndings": func(s string, op Op) (string, error) {
+			// The ending is read from new and defaults to "\n"
+			ending := op.New
+			if ending == "" {
+				ending = "\n"
+			}
+			if !isLineEnding(ending) {
+				return "", fmt.Errorf(`new must be "\n", "\r\n" or "\r", not %q`, op.New)
+			}
+			return NormalizeLineEndings(s, ending), nil
+		},
+		"ReplaceAll":             replaceOp(strings.ReplaceAll),
+		"ReplaceFirstInstanceOf": replaceOp(ReplaceFirstInstanceOf),
+		"ReplaceLastSubStr":      replaceOp(ReplaceLastSubStr),
+		"RemoveAllSubStr": func(s string, op Op) (string, error) {
+			if op.Old == "" {
+				return "", fmt.Errorf("old must not be empty")
+			}
+			return RemoveAllSubStr(s, op.Old), nil
+		},
+		"StripComments": func(s string, op Op) (string, error) {
+			style, ok := commentStyles[op.Style]
+			if !ok {
+				return "", fmt.Errorf("unknown comment style %q", op.Style)
+			}
+			return StripComments(s, style), nil
+		},
+	}
+)
+
+// commentStyles names the predefined comment styles for the StripComments operation.
+var commentStyles = map[string]CommentStyle{
+	"c":     CommentStyleC,
+	"shell": CommentStyleShell,
+	"html":  CommentStyleHTML,
+	"sql":   CommentStyleSQL,
+}
+
+// errMissingWidth is returned by operations that need a positive width.
+var errMissingWidth = errors.New("width must be a positive number of columns")
+
+// requireIndent checks the arguments shared by Indent and Outdent.
+func requireIndent(op Op) error {
+	if op.Level <= 0 {
+		return errors.New("level must be positive")
+	}
+	if op.Unit == "" {
+		return errors.New("unit must not be empty")
+	}
+	return nil
+}
+
+// simpleOp adapts a transformation that takes no arguments.
+func simpleOp(fn func(string) string) OpFunc {
+	return func(s string, _ Op) (string, error) {
+		return fn(s), nil
+	}
+}
+
+// replaceOp adapts a replacement function that uses Old and New, rejecting an empty Old.
+func replaceOp(fn func(s, old, new string) string) OpFunc {
+	return func(s string, op Op) (string, error) {
+		if op.Old == "" {
+			return "", fmt.Errorf("old must not be empty")
+		}
+		return fn(s, op.Old, op.New), nil
+	}
+}
+
+// RegisterOp makes fn available to Apply under name, replacing any operation already
+// registered with that name. It lets programs add their own operations to recipes.
+func RegisterOp(name string, fn OpFunc) {
+	opsMu.Lock()
+	defer opsMu.Unlock()
+	ops[name] = fn
+}
+
+// OpNames returns the names of every registered operation in sorted order.
+func OpNames() []string {
+	opsMu.RLock()
+	defer opsMu.RUnlock()
+	names := make([]string, 0, len(ops))
+	for name := range ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply runs the operations on s in order. It stops at the first unknown or failing
+// operation and reports its position in the list.
+func Apply(s string, list []Op) (string, error) {
+	for i, op := range list {
+		opsMu.RLock()
+		fn, ok := ops[op.Name]
+		opsMu.RUnlock()
+		if !ok {
+			return "", fmt.Errorf("op %d: unknown operation %q", i, op.Name)
+		}
+		out, err := fn(s, op)
+		if err != nil {
+			return "", fmt.Errorf("op %d (%s): %w", i, op.Name, err)
+		}
+		s = out
+	}
+	return s, nil
+}
@@ -0,0 +1,89 @@
+package purse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// runPool calls work for every index below n on a pool of workers.
+// It stops handing out indexes once ctx is done and returns ctx.Err() in that case.
+func runPool(ctx context.Context, n, workers int, work func(i int)) error {
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				work(i)
+			}
+		}()
+	}
+	var err error
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return err
+}
+
+// WorkOnStrChunksParallel splits a string by spaces and processes the chunks on a pool of workers.
+// Every chunk is processed, and all errors are joined in chunk order.
+func WorkOnStrChunksParallel(input string, workers int, processFunc func(string) error) error {
+	chunks := strings.Fields(input)
+	errs := make([]error, len(chunks))
+	runPool(context.Background(), len(chunks), workers, func(i int) {
+		if err := processFunc(chunks[i]); err != nil {
+			errs[i] = fmt.Errorf("error processing chunk %q: %w", chunks[i], err)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// MapStrChunksParallel is WorkOnStrChunksParallel for processing funcs that produce a value.
+// It returns one result per chunk in input order, leaving failed chunks empty, and joins
+// all errors in chunk order.
+func MapStrChunksParallel(input string, workers int, processFunc func(string) (string, error)) ([]string, error) {
+	chunks := strings.Fields(input)
+	out := make([]string, len(chunks))
+	errs := make([]error, len(chunks))
+	runPool(context.Background(), len(chunks), workers, func(i int) {
+		result, err := processFunc(chunks[i])
+		if err != nil {
+			errs[i] = fmt.Errorf("error processing chunk %q: %w", chunks[i], err)
+			return
+		}
+		out[i] = result
+	})
+	return out, errors.Join(errs...)
+}
+
+// MapParallel applies fn to every item on a pool of workers, returning results in input order.
+// Errors are joined in item order. If ctx is cancelled, unprocessed items are left empty
+// and the context error is included.
+func MapParallel(ctx context.Context, items []string, workers int, fn func(string) (string, error)) ([]string, error) {
+	out := make([]string, len(items))
+	errs := make([]error, len(items))
+	ctxErr := runPool(ctx, len(items), workers, func(i int) {
+		result, err := fn(items[i])
+		if err != nil {
+			errs[i] = fmt.Errorf("error processing item %q: %w", items[i], err)
+			return
+		}
+		out[i] = result
+	})
+	return out, errors.Join(append(errs, ctxErr)...)
+}
@@ -0,0 +1,78 @@
+package purse
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// pathHashLen is the number of hex characters used to keep shortened components unique.
+const pathHashLen = 8
+
+func pathHash(s string) string {
+	return ShortHash(s, pathHashLen)
+}
+
+// cutBytes returns the longest prefix of s that is at most n bytes and ends on a rune boundary.
+func cutBytes(s string, n int) string {
+	if n >= len(s) {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:max(n, 0)]
+}
+
+// shortenComponent shortens a path component to at most limit bytes, replacing the
+// cut text with a hash of the full component so distinct inputs stay distinct.
+// A short file extension is kept.
+func shortenComponent(c string, limit int) string {
+	if len(c) <= limit {
+		return c
+	}
+	hash := pathHash(c)
+	ext := filepath.Ext(c)
+	if len(ext) > pathHashLen || len(ext)+pathHashLen+1 > limit {
+		ext = ""
+	}
+	room := limit - len(hash) - len(ext) - 1
+	if room <= 0 {
+		return cutBytes(hash, limit)
+	}
+	return cutBytes(strings.TrimSuffix(c, ext), room) + "-" + hash + ext
+}
+
+// TruncatePathComponents shortens a path so no component exceeds maxComponentLen bytes
+// and the whole path fits in maxTotalLen bytes. Shortened components end in a hash of
+// their original text, so different long paths stay unique. A limit of zero or less
+// disables that check.
+func TruncatePathComponents(path string, maxComponentLen, maxTotalLen int) string {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	if maxComponentLen > 0 {
+		for i, part := range parts {
+			parts[i] = shortenComponent(part, maxComponentLen)
+		}
+	}
+	total := func() int { return len(strings.Join(parts, "/")) }
+	for maxTotalLen > 0 && total() > maxTotalLen {
+		longest := 0
+		for i, part := range parts {
+			if len(part) > len(parts[longest]) {
+				longest = i
+			}
+		}
+		over := total() - maxTotalLen
+		limit := len(parts[longest]) - over
+		if limit < pathHashLen {
+			limit = pathHashLen
+		}
+		shortened := shortenComponent(parts[longest], limit)
+		if shortened == parts[longest] {
+			// nothing left to shorten component by component, so hash the whole path
+			return filepath.FromSlash(cutBytes(pathHash(path), maxTotalLen))
+		}
+		parts[longest] = shortened
+	}
+	return filepath.FromSlash(strings.Join(parts, "/"))
+}
@@ -3,9 +3,9 @@ package purse
 
 import (
 	"fmt"
-	"math/rand"
+	"strconv"
 	"strings"
-	"time"
+	"unicode/utf8"
 )
 
 // MakeLines splits a string into lines.
@@ -18,6 +18,53 @@ func JoinLines(lines []string) string {
 	return strings.Join(lines, "\n")
 }
 
+// DetectLineEnding returns the most common line ending in a string: "\n", "\r\n" or "\r".
+// It returns "\n" when the string has no line endings.
+func DetectLineEnding(s string) string {
+	crlf := strings.Count(s, "\r\n")
+	lf := strings.Count(s, "\n") - crlf
+	cr := strings.Count(s, "\r") - crlf
+	switch {
+	case crlf > 0 && crlf >= lf && crlf >= cr:
+		return "\r\n"
+	case cr > lf:
+		return "\r"
+	default:
+		return "\n"
+	}
+}
+
+// isLineEnding reports whether s is one of the line endings DetectLineEnding returns.
+func isLineEnding(s string) bool {
+	return s == "\n" || s == "\r\n" || s == "\r"
+}
+
+// NormalizeLineEndings converts every line ending in a string to target. If target is
+// not "\n", "\r\n" or "\r", the string is returned unchanged, since any other value
+// would merge or mangle lines.
+func NormalizeLineEndings(s, target string) string {
+	if !isLineEnding(target) {
+		return s
+	}
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	if target == "\n" {
+		return s
+	}
+	return strings.ReplaceAll(s, "\n", target)
+}
+
+// SplitLines splits a string into lines on any line ending, returning the lines without
+// their endings along with the ending the string mostly uses.
+func SplitLines(s string) ([]string, string) {
+	return MakeLines(NormalizeLineEndings(s, "\n")), DetectLineEnding(s)
+}
+
+// JoinLinesWith joins an array of strings into a single string with the given line ending.
+func JoinLinesWith(lines []string, ending string) string {
+	return strings.Join(lines, ending)
+}
+
 // ReplaceLastSubStr replaces the last occurrence of a substring in a string.
 func ReplaceLastSubStr(s, old, new string) string {
 	pos := strings.LastIndex(s, old)
@@ -67,11 +114,16 @@ func CountLeadingSpaces(line string) int {
 
 // PrefixLines adds a prefix to each line of a string.
 func PrefixLines(str, prefix string) string {
-	lines := strings.Split(str, "\n")
-	for i, line := range lines {
-		lines[i] = prefix + line
-	}
-	return strings.Join(lines, "\n")
+	var sb strings.Builder
+	sb.Grow(len(str) + (strings.Count(str, "\n")+1)*len(prefix))
+	eachLine(str, func(i int, line string) {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(prefix)
+		sb.WriteString(line)
+	})
+	return sb.String()
 }
 
 // FlattenLines removes leading spaces and tabs from each line of a slice.
@@ -84,46 +136,39 @@ func FlattenLines(lines []string) []string {
 
 // Flatten removes leading spaces and tabs from all lines of a string.
 func Flatten(str string) string {
-	lines := MakeLines(str)
-	flat := FlattenLines(lines)
-	return strings.Join(flat, "")
+	var sb strings.Builder
+	sb.Grow(len(str))
+	eachLine(str, func(_ int, line string) {
+		sb.WriteString(strings.TrimLeft(line, " \t"))
+	})
+	return sb.String()
 }
 
 // TrimLeadingSpaces removes leading spaces from all lines of a string.
 func TrimLeadingSpaces(str string) string {
-	lines := strings.Split(str, "\n")
-	for i, line := range lines {
-		lines[i] = strings.TrimLeft(line, " ")
-	}
-	return strings.Join(lines, "\n")
+	return MapLines(str, func(line string, _ int) string {
+		return strings.TrimLeft(line, " ")
+	})
 }
 
 func TrimLeadingTabs(str string) string {
-	lines := strings.Split(str, "\n")
-	for i, line := range lines {
-		lines[i] = strings.TrimLeft(line, "\t")
-	}
-	return strings.Join(lines, "\n")
+	return MapLines(str, func(line string, _ int) string {
+		return strings.TrimLeft(line, "\t")
+	})
 }
 
 func TrimSomeLeadingTabs(str string, tabsToTrim int) string {
-	lines := strings.Split(str, "\n")
-	for i, line := range lines {
-		trimmed := 0
-		for j, char := range line {
-			if char == '\t' && trimmed < tabsToTrim {
-				trimmed++
-			} else {
-				lines[i] = line[j:] // Trim up to the number of tabs specified
-				break
-			}
+	return MapLines(str, func(line string, _ int) string {
+		// Lines made only of tabs become empty once any tab is trimmed
+		if tabsToTrim > 0 && line != "" && strings.TrimLeft(line, "\t") == "" {
+			return ""
 		}
-		// If the line only contained tabs, ensure it's set correctly
-		if trimmed > 0 && strings.TrimLeft(line, "\t") == "" {
-			lines[i] = ""
+		n := 0
+		for n < tabsToTrim && n < len(line) && line[n] == '\t' {
+			n++
 		}
-	}
-	return strings.Join(lines, "\n")
+		return line[n:]
+	})
 }
 
 // SliceContains checks if a slice contains a specific item.
@@ -141,6 +186,33 @@ func BackTick() string {
 	return "`"
 }
 
+// GoQuote returns s as a double-quoted Go string literal.
+func GoQuote(s string) string {
+	return strconv.Quote(s)
+}
+
+// GoRawString returns s as a Go raw string literal. Backticks in s are spliced in
+// through concatenation with double-quoted literals, and strings a raw literal
+// cannot hold (carriage returns, invalid UTF-8) fall back to GoQuote.
+func GoRawString(s string) string {
+	if strings.Contains(s, "\r") || !utf8.ValidString(s) {
+		return GoQuote(s)
+	}
+	if !strings.Contains(s, BackTick()) {
+		return BackTick() + s + BackTick()
+	}
+	var parts []string
+	for i, chunk := range strings.Split(s, BackTick()) {
+		if i > 0 {
+			parts = append(parts, GoQuote(BackTick()))
+		}
+		if chunk != "" {
+			parts = append(parts, BackTick()+chunk+BackTick())
+		}
+	}
+	return strings.Join(parts, " + ")
+}
+
 // ReplaceFirstLine replaces the first line of a string with a new line.
 func ReplaceFirstLine(input, newLine string) string {
 	lines := strings.Split(input, "\n")
@@ -202,25 +274,58 @@ func RemoveFirstLine(input string) string {
 	return input[index+1:]
 }
 
+// TrailingOption configures RemoveTrailingEmptyLines.
+type TrailingOption func(*trailingConfig)
+
+type trailingConfig struct {
+	keepFinalNewline bool
+}
+
+// KeepFinalNewline makes RemoveTrailingEmptyLines end its output with exactly one newline.
+func KeepFinalNewline() TrailingOption {
+	return func(c *trailingConfig) {
+		c.keepFinalNewline = true
+	}
+}
+
 // RemoveTrailingEmptyLines removes empty lines from the end of a string.
-func RemoveTrailingEmptyLines(input string) string {
+func RemoveTrailingEmptyLines(input string, opts ...TrailingOption) string {
+	var cfg trailingConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 	lines := strings.Split(input, "\n")
 	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
 		lines = lines[:len(lines)-1]
 	}
-	return strings.Join(lines, "\n")
+	out := strings.Join(lines, "\n")
+	if cfg.keepFinalNewline {
+		return EnsureTrailingNewline(out)
+	}
+	return out
+}
+
+// EnsureTrailingNewline appends a newline to a string unless it already ends with one.
+func EnsureTrailingNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
+
+// StripFinalNewline removes a single trailing "\n" or "\r\n" from a string.
+func StripFinalNewline(s string) string {
+	if strings.HasSuffix(s, "\r\n") {
+		return s[:len(s)-2]
+	}
+	return strings.TrimSuffix(s, "\n")
 }
 
 // RemoveEmptyLines removes all empty lines from a string.
 func RemoveEmptyLines(input string) string {
-	lines := strings.Split(input, "\n")
-	var cleanedLines []string
-	for _, line := range lines {
-		if strings.TrimSpace(line) != "" {
-			cleanedLines = append(cleanedLines, line)
-		}
-	}
-	return strings.Join(cleanedLines, "\n")
+	return FilterLines(input, func(line string, _ int) bool {
+		return strings.TrimSpace(line) != ""
+	})
 }
 
 // RemoveDuplicatesInSlice removes duplicate items from a slice.
@@ -311,10 +416,9 @@ func ReverseSlice[T any](slice []T) []T {
 // RandStr generates a random string of specified length.
 func RandStr(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	rand.Seed(time.Now().UnixNano())
 	b := make([]byte, length)
 	for i := range b {
-		b[i] = charset[rand.Intn(len(charset))]
+		b[i] = charset[randIntn(len(charset))]
 	}
 	return string(b)
 }
@@ -363,17 +467,15 @@ func WorkOnStrChunks(input string, processFunc func(string) error) error {
 	return nil
 }
 
+// KebabToCamelCase converts a kebab-case string to camelCase, leaving the first word as written.
+// Unlike ToCamelCase it splits on hyphens only, so "-foo-bar" stays "FooBar" and "foo-2d"
+// stays "foo2d".
 func KebabToCamelCase(input string) string {
-	parts := strings.Split(input, "-")
-	if len(parts) == 0 {
-		return ""
+	words := strings.Split(input, "-")
+	for i := 1; i < len(words); i++ {
+		words[i] = capitalize(words[i])
 	}
-	for i := 1; i < len(parts); i++ {
-		if len(parts[i]) > 0 {
-			parts[i] = strings.ToUpper(string(parts[i][0])) + strings.ToLower(parts[i][1:])
-		}
-	}
-	return strings.Join(parts, "")
+	return strings.Join(words, "")
 }
 
 func FindMatchInStrSlice(slice []string, str string) string {
@@ -520,3 +622,42 @@ func CountLeadingTabs(line string) int {
 	}
 	return count
 }
+
+// RepeatWith repeats a string count times with a separator between each copy.
+func RepeatWith(s string, count int, sep string) string {
+	if count <= 0 {
+		return ""
+	}
+	parts := make([]string, count)
+	for i := range parts {
+		parts[i] = s
+	}
+	return strings.Join(parts, sep)
+}
+
+// RepeatToWidth repeats a string until it is exactly width columns wide, cutting the final copy short.
+func RepeatToWidth(s string, width int) string {
+	unit := VisualWidth(s)
+	if unit == 0 || width <= 0 {
+		return ""
+	}
+	repeated := strings.Repeat(s, width/unit+1)
+	return PadToWidth(cutToWidth(repeated, width), width, AlignLeft)
+}
+
+// JoinNonEmpty joins the parts that are not blank with sep, so optional parts never
+// leave doubled separators behind: JoinNonEmpty(" - ", "a", "", "b") == "a - b".
+func JoinNonEmpty(sep string, parts ...string) string {
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if strings.TrimSpace(part) != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, sep)
+}
+
+// ConcatNonEmpty concatenates the parts that are not blank.
+func ConcatNonEmpty(parts ...string) string {
+	return JoinNonEmpty("", parts...)
+}
@@ -1,9 +1,2138 @@
 package purse_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/phillip-england/purse"
 )
 
 func TestMain(t *testing.T) {
 
 }
+
+func TestAlignColumns(t *testing.T) {
+	rows := [][]string{
+		{"name", "size", "kind"},
+		{"purse.go", "12602", "go"},
+		{"LICENSE", "1071"},
+		{"README"},
+		{"go.mod", "120", "mod"},
+	}
+	got := purse.AlignColumns(rows, purse.TableOpts{
+		Align: []purse.Align{purse.AlignLeft, purse.AlignRight},
+		Sep:   " | ",
+	})
+	want := "name     |  size | kind\n" +
+		"purse.go | 12602 | go\n" +
+		"LICENSE  |  1071\n" +
+		"README\n" +
+		"go.mod   |   120 | mod"
+	if got != want {
+		t.Fatalf("AlignColumns:\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestFitLabel(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		align purse.Align
+		want  string
+	}{
+		{"status", 10, purse.AlignLeft, "status    "},
+		{"status", 10, purse.AlignRight, "    status"},
+		{"status", 9, purse.AlignCenter, " status  "},
+		{"connected to server", 10, purse.AlignLeft, "connected…"},
+		{"abc", 0, purse.AlignLeft, ""},
+	}
+	for _, tt := range tests {
+		if got := purse.FitLabel(tt.in, tt.width, tt.align); got != tt.want {
+			t.Errorf("FitLabel(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	c := purse.NewCursor("key: {{ one }} and {{ two }}!")
+	head, ok := c.ReadUntil(":")
+	if !ok || head != "key" || c.Pos() != 3 {
+		t.Fatalf("ReadUntil = %q, %v at %d", head, ok, c.Pos())
+	}
+	var found []string
+	for {
+		s, ok := c.ReadBetween("{{", "}}")
+		if !ok {
+			break
+		}
+		found = append(found, s)
+	}
+	if len(found) != 2 || found[0] != "{{ one }}" || found[1] != "{{ two }}" {
+		t.Fatalf("ReadBetween = %q", found)
+	}
+	if c.Remaining() != "!" {
+		t.Fatalf("Remaining = %q", c.Remaining())
+	}
+	if c.SeekTo("{{") {
+		t.Fatal("SeekTo found a match past the end")
+	}
+}
+
+func TestStripComments(t *testing.T) {
+	tests := []struct {
+		in    string
+		style purse.CommentStyle
+		want  string
+	}{
+		{"x := 1 // one\ny := \"// not\" /* two */+ `/*raw*/`", purse.CommentStyleC, "x := 1 \ny := \"// not\" + `/*raw*/`"},
+		{"s := \"esc \\\" // still\" // gone", purse.CommentStyleC, "s := \"esc \\\" // still\" "},
+		{"echo '#hash' # note\nls", purse.CommentStyleShell, "echo '#hash' \nls"},
+		{"echo 'C:\\' # note\necho \"a\\\" # b\"", purse.CommentStyleShell, "echo 'C:\\' \necho \"a\\\" # b\""},
+		{"<p>don't</p><!-- hidden --><b>", purse.CommentStyleHTML, "<p>don't</p><b>"},
+		{"SELECT '--' -- why\nFROM t", purse.CommentStyleSQL, "SELECT '--' \nFROM t"},
+		// SQL escapes quotes by doubling them, so a backslash does not escape the closing quote
+		{"SELECT 'C:\\' -- path\nFROM t", purse.CommentStyleSQL, "SELECT 'C:\\' \nFROM t"},
+		{"SELECT 'it''s -- here' -- gone", purse.CommentStyleSQL, "SELECT 'it''s -- here' "},
+	}
+	for _, tt := range tests {
+		if got := purse.StripComments(tt.in, tt.style); got != tt.want {
+			t.Errorf("StripComments(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	if got := purse.RepeatWith("ab", 3, ", "); got != "ab, ab, ab" {
+		t.Errorf("RepeatWith = %q", got)
+	}
+	if got := purse.RepeatToWidth("-·", 5); got != "-·-·-" {
+		t.Errorf("RepeatToWidth = %q", got)
+	}
+}
+
+func TestIndent(t *testing.T) {
+	doc := "a\n\n  b\n    c"
+	if got := purse.DetectIndentUnit(doc); got != "  " {
+		t.Errorf("DetectIndentUnit = %q", got)
+	}
+	if got := purse.DetectIndentUnit("a\n\tb"); got != "\t" {
+		t.Errorf("DetectIndentUnit = %q", got)
+	}
+	indented := purse.Indent(doc, 1, "  ")
+	if indented != "  a\n\n    b\n      c" {
+		t.Errorf("Indent = %q", indented)
+	}
+	if got := purse.Outdent(indented, 1, "  "); got != doc {
+		t.Errorf("Outdent = %q", got)
+	}
+}
+
+func TestRule(t *testing.T) {
+	if got := purse.Rule(5, '─'); got != "─────" {
+		t.Errorf("Rule = %q", got)
+	}
+	if got := purse.Rule(13, '─', purse.WithTitle("Title")); got != "─── Title ───" {
+		t.Errorf("Rule with title = %q", got)
+	}
+}
+
+func TestVisualWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"abc", 3},
+		{"日本語", 6},
+		{"é", 1},
+		{"👍", 2},
+	}
+	for _, tt := range tests {
+		if got := purse.VisualWidth(tt.in); got != tt.want {
+			t.Errorf("VisualWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+	if got := purse.PadToWidth("日本", 6, purse.AlignRight); got != "  日本" {
+		t.Errorf("PadToWidth = %q", got)
+	}
+	if got := purse.Truncate("日本語です", 7, "…"); got != "日本語…" {
+		t.Errorf("Truncate = %q", got)
+	}
+	got := purse.AlignColumns([][]string{{"日本", "x"}, {"ab", "y"}}, purse.TableOpts{})
+	if got != "日本 x\nab   y" {
+		t.Errorf("AlignColumns = %q", got)
+	}
+}
+
+func TestReplaceAtOffsets(t *testing.T) {
+	got, err := purse.ReplaceAtOffsets("hello big world", []purse.Edit{
+		{Start: 10, End: 15, Text: "there"},
+		{Start: 0, End: 5, Text: "HELLO"},
+		{Start: 6, End: 6, Text: "very "},
+	})
+	if err != nil || got != "HELLO very big there" {
+		t.Fatalf("ReplaceAtOffsets = %q, %v", got, err)
+	}
+	if _, err := purse.ReplaceAtOffsets("abcdef", []purse.Edit{{0, 3, "x"}, {2, 4, "y"}}); err == nil {
+		t.Fatal("expected an overlap error")
+	}
+	if _, err := purse.ReplaceAtOffsets("abc", []purse.Edit{{2, 9, "x"}}); err == nil {
+		t.Fatal("expected a range error")
+	}
+}
+
+func TestBuilderPlaceholders(t *testing.T) {
+	b := purse.NewBuilder()
+	b.Write("items: ")
+	if err := b.ReservePlaceholder("count"); err != nil {
+		t.Fatal(err)
+	}
+	b.Write("\n")
+	for _, item := range []string{"a", "b", "c"} {
+		b.Writef("- %s\n", item)
+	}
+	if _, err := b.Build(); err == nil {
+		t.Fatal("expected an unresolved placeholder error")
+	}
+	if err := b.ResolvePlaceholder("count", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.ResolvePlaceholder("missing", "x"); err == nil {
+		t.Fatal("expected an error for an unknown placeholder")
+	}
+	if err := b.ReservePlaceholder("count"); err == nil {
+		t.Fatal("expected an error for a duplicate placeholder")
+	}
+	if err := b.ReservePlaceholder(""); err == nil {
+		t.Fatal("expected an error for an empty placeholder name")
+	}
+	got, err := b.Build()
+	if err != nil || got != "items: 3\n- a\n- b\n- c\n" {
+		t.Fatalf("Build = %q, %v", got, err)
+	}
+}
+
+func BenchmarkBuilderPlaceholders(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sb := purse.NewBuilder()
+		for j := 0; j < 10000; j++ {
+			name := strconv.Itoa(j)
+			sb.Write("count: ")
+			if err := sb.ReservePlaceholder(name); err != nil {
+				b.Fatal(err)
+			}
+			sb.ResolvePlaceholder(name, name)
+		}
+		if _, err := sb.Build(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBuilderWrite(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		sb := purse.NewBuilder()
+		sb.ReservePlaceholder("total")
+		for j := 0; j < 10000; j++ {
+			sb.Write("line of generated output\n")
+		}
+		sb.ResolvePlaceholder("total", "10000")
+		if _, err := sb.Build(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestLineFormatter(t *testing.T) {
+	f := purse.NewLineFormatter(" ").
+		AddField("level", 5, purse.AlignLeft).
+		AddField("code", 4, purse.AlignRight).
+		AddField("msg", 0, purse.AlignLeft)
+	if got := f.Format("INFO", "200", "ok"); got != "INFO   200 ok" {
+		t.Errorf("Format = %q", got)
+	}
+	got := f.FormatMap(map[string]string{"level": "WARNING", "msg": "slow"})
+	if got != "WARN…      slow" {
+		t.Errorf("FormatMap = %q", got)
+	}
+}
+
+func TestReplaceAllPairs(t *testing.T) {
+	got := purse.ReplaceAllPairs("a ab b", map[string]string{"a": "b", "b": "a", "ab": "X"})
+	if got != "b X a" {
+		t.Errorf("ReplaceAllPairs = %q", got)
+	}
+	if got := purse.NewReplacer("<", "&lt;", ">", "&gt;").Replace("<b>"); got != "&lt;b&gt;" {
+		t.Errorf("Replacer = %q", got)
+	}
+}
+
+func TestLineRanges(t *testing.T) {
+	doc := "a\nb\nc\nd"
+	if got := purse.GetLines(doc, 1, -1); got != "b\nc" {
+		t.Errorf("GetLines = %q", got)
+	}
+	if got := purse.ReplaceLines(doc, -3, -1, "x"); got != "a\nx\nd" {
+		t.Errorf("ReplaceLines = %q", got)
+	}
+	if got := purse.InsertLineAt(doc, -1, "x"); got != "a\nb\nc\nx\nd" {
+		t.Errorf("InsertLineAt = %q", got)
+	}
+	if got := purse.InsertLineAt(doc, 4, "e"); got != "a\nb\nc\nd\ne" {
+		t.Errorf("InsertLineAt end = %q", got)
+	}
+	if got := purse.RemoveLineAt(doc, -1); got != "a\nb\nc" {
+		t.Errorf("RemoveLineAt = %q", got)
+	}
+}
+
+func TestSnipAtWordBoundary(t *testing.T) {
+	tests := []struct {
+		in       string
+		max      int
+		ellipsis []string
+		want     string
+	}{
+		{"the quick brown fox", 12, nil, "the quick"},
+		{"the quick brown fox", 15, []string{"..."}, "the quick..."},
+		{"the quick brown fox", 9, nil, "the quick"},
+		{"supercalifragilistic", 5, nil, "super"},
+		{"short", 10, []string{"…"}, "short"},
+	}
+	for _, tt := range tests {
+		if got := purse.SnipAtWordBoundary(tt.in, tt.max, tt.ellipsis...); got != tt.want {
+			t.Errorf("SnipAtWordBoundary(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*.go", "purse.go", true},
+		{"*.go", "dir/purse.go", true},
+		{"*.go", "purse.gox", false},
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"[a-c]*", "banana", true},
+		{"[!a-c]*", "banana", false},
+		{"*_[0-9][0-9]", "id_42", true},
+		{`\*lit`, "*lit", true},
+		{"a*b*c", "aXXbYYc", true},
+		{"a*b*c", "aXXbYY", false},
+		{"[abc", "a", false},
+	}
+	for _, tt := range tests {
+		if got := purse.MatchGlob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+	got := purse.FilterGlob([]string{"getName", "setName", "getAge"}, "get*")
+	if len(got) != 2 || got[0] != "getName" || got[1] != "getAge" {
+		t.Errorf("FilterGlob = %q", got)
+	}
+}
+
+func TestUnderline(t *testing.T) {
+	if got := purse.Underline("Usage", '='); got != "Usage\n=====" {
+		t.Errorf("Underline = %q", got)
+	}
+	if got := purse.Underline("日本", '-'); got != "日本\n----" {
+		t.Errorf("Underline wide = %q", got)
+	}
+}
+
+func TestLists(t *testing.T) {
+	got := purse.BulletList([]string{"one", "\ttwo\nwrapped", "three"}, "-")
+	if got != "- one\n  - two\n    wrapped\n- three" {
+		t.Errorf("BulletList = %q", got)
+	}
+	got = purse.Checklist([]purse.CheckItem{
+		{Text: "build", Done: true},
+		{Text: "release", Children: []purse.CheckItem{{Text: "tag", Done: true}, {Text: "notes"}}},
+	})
+	if got != "- [x] build\n- [ ] release\n  - [x] tag\n  - [ ] notes" {
+		t.Errorf("Checklist = %q", got)
+	}
+}
+
+func TestGoRawString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain\ntext", "`plain\ntext`"},
+		{"a`b", "`a` + \"`\" + `b`"},
+		{"`", "\"`\""},
+		{"crlf\r\n", "\"crlf\\r\\n\""},
+	}
+	for _, tt := range tests {
+		if got := purse.GoRawString(tt.in); got != tt.want {
+			t.Errorf("GoRawString(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFinalNewline(t *testing.T) {
+	if got := purse.RemoveTrailingEmptyLines("a\nb\n\n  \n", purse.KeepFinalNewline()); got != "a\nb\n" {
+		t.Errorf("RemoveTrailingEmptyLines = %q", got)
+	}
+	if got := purse.RemoveTrailingEmptyLines("a\n\n"); got != "a" {
+		t.Errorf("RemoveTrailingEmptyLines = %q", got)
+	}
+	if got := purse.EnsureTrailingNewline("a"); got != "a\n" {
+		t.Errorf("EnsureTrailingNewline = %q", got)
+	}
+	if got := purse.StripFinalNewline("a\n\n"); got != "a\n" {
+		t.Errorf("StripFinalNewline = %q", got)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	got, err := purse.SplitArgs(`run "hello world" 'it''s' a\ b "say \"hi\" \n" ''`)
+	want := []string{"run", "hello world", "its", "a b", `say "hi" \n`, ""}
+	if err != nil || strings.Join(got, "|") != strings.Join(want, "|") || len(got) != len(want) {
+		t.Fatalf("SplitArgs = %q, %v", got, err)
+	}
+	if _, err := purse.SplitArgs(`echo "open`); err == nil {
+		t.Fatal("expected an unterminated quote error")
+	}
+	csv := purse.Tokenizer{Delims: ",", Quotes: `"`}
+	got, err = csv.Split(`a,"b,c",d`)
+	if err != nil || strings.Join(got, "|") != "a|b,c|d" {
+		t.Fatalf("Tokenizer.Split = %q, %v", got, err)
+	}
+}
+
+func TestSnippetStore(t *testing.T) {
+	st := purse.NewSnippetStore()
+	ids := st.Dedup([]string{"header", "body", "header"})
+	if ids[0] != ids[2] || st.Len() != 2 {
+		t.Fatalf("Dedup = %v, Len = %d", ids, st.Len())
+	}
+	dir := t.TempDir()
+	if err := st.Save(dir); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := purse.LoadSnippetStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, ok := loaded.Get(ids[1]); !ok || s != "body" {
+		t.Fatalf("Get = %q, %v", s, ok)
+	}
+}
+
+func TestWorkOnStrChunksParallel(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	err := purse.WorkOnStrChunksParallel("a b bad c worse", 3, func(chunk string) error {
+		mu.Lock()
+		seen[chunk] = true
+		mu.Unlock()
+		if strings.HasPrefix(chunk, "bad") || strings.HasPrefix(chunk, "worse") {
+			return errors.New("rejected")
+		}
+		return nil
+	})
+	if len(seen) != 5 {
+		t.Fatalf("processed %d chunks, want 5", len(seen))
+	}
+	want := "error processing chunk \"bad\": rejected\nerror processing chunk \"worse\": rejected"
+	if err == nil || err.Error() != want {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestMapStrChunksParallel(t *testing.T) {
+	got, err := purse.MapStrChunksParallel("d bad c b worse a", 3, func(chunk string) (string, error) {
+		if len(chunk) > 1 {
+			return "", errors.New("rejected")
+		}
+		time.Sleep(time.Duration(chunk[0]-'a') * time.Millisecond)
+		return strings.ToUpper(chunk), nil
+	})
+	if strings.Join(got, ",") != "D,,C,B,,A" {
+		t.Fatalf("results = %q", got)
+	}
+	want := "error processing chunk \"bad\": rejected\nerror processing chunk \"worse\": rejected"
+	if err == nil || err.Error() != want {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestMapParallel(t *testing.T) {
+	items := []string{"a", "b", "c", "d"}
+	got, err := purse.MapParallel(context.Background(), items, 2, func(s string) (string, error) {
+		return strings.ToUpper(s), nil
+	})
+	if err != nil || strings.Join(got, "") != "ABCD" {
+		t.Fatalf("MapParallel = %q, %v", got, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = purse.MapParallel(ctx, items, 2, func(s string) (string, error) {
+		return s, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestSplitFrontmatter(t *testing.T) {
+	tests := []struct {
+		in, fm, body string
+		ok           bool
+	}{
+		{"---\ntitle: x\ntags: [a]\n---\n# Body\n", "title: x\ntags: [a]", "# Body\n", true},
+		{"+++\ntitle = 'x'\n+++", "title = 'x'", "", true},
+		{"---\r\na: 1\r\n---\r\nbody", "a: 1", "body", true},
+		{"---\n---\nbody", "", "body", true},
+		{"---\na: 1\nno close", "", "---\na: 1\nno close", false},
+		{"# Title\n---\n", "", "# Title\n---\n", false},
+	}
+	for _, tt := range tests {
+		fm, body, ok := purse.SplitFrontmatter(tt.in)
+		if fm != tt.fm || body != tt.body || ok != tt.ok {
+			t.Errorf("SplitFrontmatter(%q) = %q, %q, %v", tt.in, fm, body, ok)
+		}
+	}
+}
+
+func TestFindBestLineMatch(t *testing.T) {
+	hay := "package main\n\nfunc main() {\n\tfmt.Println(\"hello\")\n\treturn\n}"
+	i, score, ok := purse.FindBestLineMatch(hay, "func main() {\n    fmt.Println(\"helo\")", 0.2)
+	if !ok || i != 2 || score < 0.9 {
+		t.Fatalf("FindBestLineMatch = %d, %f, %v", i, score, ok)
+	}
+	if _, _, ok := purse.FindBestLineMatch(hay, "something else entirely", 0.1); ok {
+		t.Fatal("expected no match")
+	}
+	if got := purse.Levenshtein("kitten", "sitting"); got != 3 {
+		t.Errorf("Levenshtein = %d", got)
+	}
+}
+
+func TestNumberLines(t *testing.T) {
+	src := "a\nb\nc"
+	if got := purse.NumberLines(src, purse.NumberOpts{Start: 9}); got != " 9 | a\n10 | b\n11 | c" {
+		t.Errorf("NumberLines = %q", got)
+	}
+	if got := purse.HighlightLine(src, 2, "> "); got != "  a\n> b\n  c" {
+		t.Errorf("HighlightLine = %q", got)
+	}
+	numbered := purse.NumberLines(src, purse.NumberOpts{Sep: ": "})
+	if got := purse.HighlightLine(numbered, 3, "-> "); got != "   1: a\n   2: b\n-> 3: c" {
+		t.Errorf("combined = %q", got)
+	}
+}
+
+func TestANSI(t *testing.T) {
+	red := "\x1b[31mhello\x1b[0m world"
+	if got := purse.StripANSI(red); got != "hello world" {
+		t.Errorf("StripANSI = %q", got)
+	}
+	if got := purse.StripANSI("\x1b]8;;http://x\x1b\\link\x1b]8;;\a"); got != "link" {
+		t.Errorf("StripANSI osc = %q", got)
+	}
+	if got := purse.VisualWidth(red); got != 11 {
+		t.Errorf("VisualWidth = %d", got)
+	}
+	if got := purse.Truncate(red, 4, "…"); got != "\x1b[31mhel…\x1b[0m" {
+		t.Errorf("Truncate = %q", got)
+	}
+	if got := purse.PadToWidth("\x1b[1mab\x1b[0m", 4, purse.AlignLeft); got != "\x1b[1mab\x1b[0m  " {
+		t.Errorf("PadToWidth = %q", got)
+	}
+	if got := purse.StripANSI("\x1bé"); got != "é" {
+		t.Errorf("StripANSI multibyte = %q", got)
+	}
+	if got := purse.StripANSI("a\x1bMb"); got != "ab" {
+		t.Errorf("StripANSI two-byte = %q", got)
+	}
+	if got := purse.StripANSI("a\x1b\nb\x1bac"); got != "a\nbac" {
+		t.Errorf("StripANSI lone ESC = %q", got)
+	}
+	if got := purse.VisualWidth("\x1b世"); got != 2 {
+		t.Errorf("VisualWidth multibyte = %d", got)
+	}
+}
+
+func TestANSICounted(t *testing.T) {
+	red := "\x1b[31mhello\x1b[0m world"
+	if got := purse.VisualWidth(red, purse.CountANSI()); got != 18 {
+		t.Errorf("VisualWidth = %d", got)
+	}
+	if got := purse.Truncate(red, 6, "…", purse.CountANSI()); got != "\x1b[31mh…" {
+		t.Errorf("Truncate = %q", got)
+	}
+	if got := purse.PadToWidth("\x1b[1mab", 8, purse.AlignRight, purse.CountANSI()); got != "   \x1b[1mab" {
+		t.Errorf("PadToWidth = %q", got)
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := purse.Translate("Hello, World", "a-z", "A-Z"); got != "HELLO, WORLD" {
+		t.Errorf("Translate = %q", got)
+	}
+	if got := purse.Translate("a-b_c", "-_", "."); got != "a.b.c" {
+		t.Errorf("Translate short to = %q", got)
+	}
+	if got := purse.Delete("ph0ne: 555-12", "0-9-"); got != "phne: " {
+		t.Errorf("Delete = %q", got)
+	}
+}
+
+func TestMakeLinesInterned(t *testing.T) {
+	pool := purse.NewLinePool()
+	lines := purse.MakeLinesInterned("GET /\nGET /\nPOST /a\nGET /", pool)
+	if len(lines) != 4 || pool.Len() != 2 || lines[3] != "GET /" {
+		t.Fatalf("lines = %q, pool = %d", lines, pool.Len())
+	}
+	if unsafe.StringData(lines[0]) != unsafe.StringData(lines[3]) {
+		t.Fatal("identical lines do not share memory")
+	}
+}
+
+func TestContext(t *testing.T) {
+	excerpt, pos := purse.Context("let x = ?oops; done", 8, 4)
+	if excerpt != "x = ?oop" || pos != 4 {
+		t.Errorf("Context = %q, %d", excerpt, pos)
+	}
+	excerpt, pos = purse.Context("héllo", 2, 1)
+	if excerpt != "hé" || pos != 1 {
+		t.Errorf("Context mid-rune = %q, %d", excerpt, pos)
+	}
+	excerpt, pos = purse.Context("abc", 99, 2)
+	if excerpt != "bc" || pos != 2 {
+		t.Errorf("Context clamped = %q, %d", excerpt, pos)
+	}
+}
+
+func TestRenderDiagnostic(t *testing.T) {
+	src := "func main() {\n\tx := undefined + 1\n}"
+	offset := strings.Index(src, "undefined")
+	want := "\tx := undefined + 1\n\t     ^~~~~~~~~ unknown name"
+	if got := purse.RenderDiagnostic(src, offset, "unknown name"); got != want {
+		t.Errorf("RenderDiagnostic =\n%s\nwant\n%s", got, want)
+	}
+	if line, col := purse.LineCol(src, offset); line != 2 || col != 7 {
+		t.Errorf("LineCol = %d, %d", line, col)
+	}
+}
+
+func TestFold(t *testing.T) {
+	if got := purse.ReplaceAllFold("Go is GREAT, go!", "go", "Rust"); got != "Rust is GREAT, Rust!" {
+		t.Errorf("ReplaceAllFold = %q", got)
+	}
+	if got := purse.IndexFold("ΣΊΣΥΦΟΣ", "σίσυφος"); got != 0 {
+		t.Errorf("IndexFold greek = %d", got)
+	}
+	if got := purse.IndexFold("temp 5K", "k"); got != 6 {
+		t.Errorf("IndexFold kelvin = %d", got)
+	}
+	if !purse.ContainsFold("Hello", "ELL") || purse.ContainsFold("Hello", "xyz") {
+		t.Error("ContainsFold")
+	}
+	if !purse.SliceContainsFold([]string{"Alpha", "Beta"}, "beta") {
+		t.Error("SliceContainsFold")
+	}
+}
+
+func TestParseKeyValues(t *testing.T) {
+	kv, err := purse.ParseKeyValues("# comment\nname = \"purse\"\nport: 80\nport = 8080", "=")
+	if err == nil {
+		t.Fatalf("expected a missing separator error, got %v", kv)
+	}
+	kv, err = purse.ParseKeyValues("name = \"purse\"\n\nport = 80\nport = 8080", "=", purse.OnDuplicate(purse.DuplicateFirst))
+	if err != nil || kv["name"] != "purse" || kv["port"] != "80" {
+		t.Fatalf("ParseKeyValues = %v, %v", kv, err)
+	}
+	if _, err := purse.ParseKeyValues("a=1\na=2", "=", purse.OnDuplicate(purse.DuplicateError)); err == nil {
+		t.Fatal("expected a duplicate key error")
+	}
+	sections, err := purse.ParseSections("root = yes\n[server]\nhost = localhost\n; note\n[db]\nname = 'app'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sections[""]["root"] != "yes" || sections["server"]["host"] != "localhost" || sections["db"]["name"] != "app" {
+		t.Fatalf("ParseSections = %v", sections)
+	}
+}
+
+func TestSplitDocuments(t *testing.T) {
+	docs := purse.SplitDocuments("---\na: 1\n\n---\n\nb: 2\nc: 3\n---\n---  \n", "---")
+	if len(docs) != 2 || docs[0] != "a: 1" || docs[1] != "b: 2\nc: 3" {
+		t.Fatalf("SplitDocuments = %q", docs)
+	}
+}
+
+func TestFields(t *testing.T) {
+	ps := "PID TTY      TIME CMD\n  1 ?    00:00:01 init\n 42 pts/0 00:00:00 bash"
+	if got := purse.Field("  1 ?    00:00:01 init", -1); got != "init" {
+		t.Errorf("Field = %q", got)
+	}
+	if got := purse.Fields2D(ps); len(got) != 3 || got[2][1] != "pts/0" {
+		t.Errorf("Fields2D = %q", got)
+	}
+	if got := purse.SelectColumns(ps, 0, -1); got != "PID CMD\n1 init\n42 bash" {
+		t.Errorf("SelectColumns = %q", got)
+	}
+}
+
+func TestReadDelimitedInterned(t *testing.T) {
+	records, err := purse.ReadDelimitedInterned(strings.NewReader("us\tactive\nde\t\"in\tactive\"\nus\tactive\n"), '\t')
+	if err != nil || len(records) != 3 || records[1][1] != "in\tactive" {
+		t.Fatalf("ReadDelimitedInterned = %q, %v", records, err)
+	}
+	if unsafe.StringData(records[0][1]) != unsafe.StringData(records[2][1]) {
+		t.Fatal("repeated fields do not share memory")
+	}
+}
+
+func TestWrapBlock(t *testing.T) {
+	block := purse.WrapBlock("a=1\nb=2", "# BEGIN managed", "# END managed")
+	doc := "x=0\n" + block + "\ny=3"
+	inner, ok := purse.UnwrapBlock(doc, "# BEGIN managed", "# END managed")
+	if !ok || inner != "a=1\nb=2" {
+		t.Fatalf("UnwrapBlock = %q, %v", inner, ok)
+	}
+	if _, ok := purse.UnwrapBlock("# BEGIN managed\na=1", "# BEGIN managed", "# END managed"); ok {
+		t.Fatal("expected no block without a footer")
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	if got := purse.AllIndexes("aaaa {{x}} b {{y}}", "{{"); len(got) != 2 || got[0] != 5 || got[1] != 13 {
+		t.Errorf("AllIndexes = %v", got)
+	}
+	if got := purse.AllIndexes("aaaa", "aa"); len(got) != 2 || got[1] != 2 {
+		t.Errorf("AllIndexes overlapping = %v", got)
+	}
+	if got := purse.CountOccurrences("aaaa", "aa"); got != 2 {
+		t.Errorf("CountOccurrences = %d", got)
+	}
+	if purse.CountLines("") != 0 || purse.CountLines("a\nb\n") != 3 {
+		t.Error("CountLines")
+	}
+}
+
+func TestTruncatePathComponents(t *testing.T) {
+	long := strings.Repeat("segment", 10)
+	a := purse.TruncatePathComponents("cache/"+long+"a.json", 30, 0)
+	b := purse.TruncatePathComponents("cache/"+long+"b.json", 30, 0)
+	if a == b {
+		t.Fatalf("shortened paths collide: %q", a)
+	}
+	for _, part := range strings.Split(a, "/") {
+		if len(part) > 30 {
+			t.Fatalf("component %q is longer than 30 bytes", part)
+		}
+	}
+	if !strings.HasSuffix(a, ".json") {
+		t.Fatalf("extension lost: %q", a)
+	}
+	c := purse.TruncatePathComponents("cache/"+long+"/"+long+"/file.txt", 40, 60)
+	if len(c) > 60 {
+		t.Fatalf("path %q is longer than 60 bytes", c)
+	}
+	if got := purse.TruncatePathComponents("short/path", 30, 100); got != "short/path" {
+		t.Fatalf("short path changed: %q", got)
+	}
+}
+
+func TestWhitespace(t *testing.T) {
+	if got := purse.NormalizeWhitespace("a  \t b\n\tc   d"); got != "a b\n c d" {
+		t.Errorf("NormalizeWhitespace = %q", got)
+	}
+	if got := purse.TrimTrailingSpaces("a  \nb\t\n c "); got != "a\nb\n c" {
+		t.Errorf("TrimTrailingSpaces = %q", got)
+	}
+	if got := purse.ExpandTabs("\tx\nab\tc", 4); got != "    x\nab  c" {
+		t.Errorf("ExpandTabs = %q", got)
+	}
+	if got := purse.UnexpandTabs("      x\n  \ty", 4); got != "\t  x\n\ty" {
+		t.Errorf("UnexpandTabs = %q", got)
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	src := "<p>{{ a }}</p>\n<b>{{ b }} {{ c }}</b>\n{{ open"
+	spans := purse.FindAll(src, "{{", "}}")
+	if len(spans) != 3 {
+		t.Fatalf("FindAll = %+v", spans)
+	}
+	if spans[1].Text != "{{ b }}" || spans[1].Line != 2 || src[spans[1].Start:spans[1].End] != spans[1].Text {
+		t.Errorf("FindAll span = %+v", spans[1])
+	}
+	if got := purse.FindAllLineNumbers(src, "{{", "}}"); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("FindAllLineNumbers = %v", got)
+	}
+	if got := purse.FindAll("'a' 'b'", "'", "'"); len(got) != 2 || got[1].Text != "'b'" {
+		t.Errorf("FindAll same delimiter = %+v", got)
+	}
+}
+
+func TestDiffSlices(t *testing.T) {
+	want := []string{"a", "b", "c", "d"}
+	got := []string{"b", "c", "a", "e"}
+	diff := purse.DiffSlices(want, got)
+	if diff.String() != "- d\n+ e\n~ a (0 -> 2)" {
+		t.Errorf("DiffSlices =\n%s", diff)
+	}
+	if unordered := purse.DiffSlices(want, got, purse.IgnoreOrder()); len(unordered.Moved) != 0 || len(unordered.Added) != 1 {
+		t.Errorf("DiffSlices IgnoreOrder = %+v", unordered)
+	}
+	if !purse.DiffSlices([]string{"x", "x"}, []string{"x", "x"}).Equal() {
+		t.Error("identical slices reported a difference")
+	}
+	lines := diffBenchLines(3000)
+	reversed := make([]string, len(lines))
+	for i, line := range lines {
+		reversed[len(lines)-1-i] = line
+	}
+	if moved := len(purse.DiffSlices(lines, reversed).Moved); moved != len(lines)-1 {
+		t.Errorf("reversed slice moved %d items, want %d", moved, len(lines)-1)
+	}
+	shifted := append([]string{lines[len(lines)-1]}, lines[:len(lines)-1]...)
+	if diff := purse.DiffSlices(lines, shifted); len(diff.Moved) != 1 || diff.Moved[0].Item != lines[len(lines)-1] {
+		t.Errorf("shifted slice moved %+v", diff.Moved)
+	}
+}
+
+// diffBenchLines returns n distinct lines, as in a source file being synced.
+func diffBenchLines(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d: fmt.Println(%d)", i, i*7)
+	}
+	return lines
+}
+
+func TestMarkdownStructure(t *testing.T) {
+	doc := "# Title #\n\nIntro\n=====\n\n- step:\n  ```go\n  fmt.Println(1)\n  ```\n\n~~~\n# not a heading\n~~~\n\n## Usage\n"
+	fences := purse.ExtractCodeFences(doc)
+	if len(fences) != 2 || fences[0].Lang != "go" || fences[0].Body != "fmt.Println(1)" || fences[1].Body != "# not a heading" {
+		t.Fatalf("ExtractCodeFences = %+v", fences)
+	}
+	headings := purse.ListHeadings(doc)
+	want := []purse.Heading{{1, "Title", 1}, {1, "Intro", 3}, {2, "Usage", 15}}
+	if len(headings) != len(want) {
+		t.Fatalf("ListHeadings = %+v", headings)
+	}
+	for i := range want {
+		if headings[i] != want[i] {
+			t.Errorf("heading %d = %+v, want %+v", i, headings[i], want[i])
+		}
+	}
+}
+
+func TestPickWeighted(t *testing.T) {
+	purse.SetRandSource(rand.NewSource(1))
+	counts := map[string]int{}
+	options := []purse.WeightedString{{"common", 9}, {"rare", 1}, {"never", 0}}
+	for i := 0; i < 1000; i++ {
+		pick, err := purse.PickWeighted(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[pick]++
+	}
+	if counts["never"] != 0 || counts["common"] < 800 || counts["rare"] < 50 {
+		t.Errorf("PickWeighted counts = %v", counts)
+	}
+	if _, err := purse.PickWeighted(nil); err == nil {
+		t.Error("expected an error with no options")
+	}
+	s := purse.RandStrWeightedCharset(50, map[rune]float64{'a': 1, 'b': 1, 'z': 0})
+	if len(s) != 50 || strings.ContainsRune(s, 'z') {
+		t.Errorf("RandStrWeightedCharset = %q", s)
+	}
+	purse.SetRandSource(rand.NewSource(7))
+	first := purse.RandStr(16)
+	purse.SetRandSource(rand.NewSource(7))
+	if second := purse.RandStr(16); first != second {
+		t.Errorf("RandStr is not reproducible: %q != %q", first, second)
+	}
+}
+
+func TestSplitTags(t *testing.T) {
+	src := `<div class="a>b" hidden>1 < 2<!-- <x> --><br/></div>`
+	tokens := purse.SplitTags(src)
+	want := []string{`<div class="a>b" hidden>`, "1 < 2", "<!-- <x> -->", "<br/>", "</div>"}
+	if strings.Join(tokens, "|") != strings.Join(want, "|") {
+		t.Fatalf("SplitTags = %q", tokens)
+	}
+	if v, ok := purse.GetAttr(tokens[0], "CLASS"); !ok || v != "a>b" {
+		t.Errorf("GetAttr class = %q, %v", v, ok)
+	}
+	if v, ok := purse.GetAttr(tokens[0], "hidden"); !ok || v != "" {
+		t.Errorf("GetAttr hidden = %q, %v", v, ok)
+	}
+	if v, ok := purse.GetAttr(`<a href=/home id='x'>`, "href"); !ok || v != "/home" {
+		t.Errorf("GetAttr href = %q, %v", v, ok)
+	}
+	if _, ok := purse.GetAttr(`<a href="/">`, "id"); ok {
+		t.Error("GetAttr found a missing attribute")
+	}
+}
+
+func TestFakeData(t *testing.T) {
+	purse.SetRandSource(rand.NewSource(3))
+	if c := purse.RandHexColor(); len(c) != 7 || c[0] != '#' {
+		t.Errorf("RandHexColor = %q", c)
+	}
+	if ip := purse.RandIPv4(); len(strings.Split(ip, ".")) != 4 {
+		t.Errorf("RandIPv4 = %q", ip)
+	}
+	if mac := purse.RandMAC(); len(mac) != 17 {
+		t.Errorf("RandMAC = %q", mac)
+	}
+	if d := purse.RandDomain(); !strings.Contains(d, ".") {
+		t.Errorf("RandDomain = %q", d)
+	}
+	s := purse.RandSentence(5)
+	if len(strings.Fields(s)) != 5 || !strings.HasSuffix(s, ".") || strings.ToUpper(s[:1]) != s[:1] {
+		t.Errorf("RandSentence = %q", s)
+	}
+}
+
+func TestMapLines(t *testing.T) {
+	got := purse.MapLines("a\nb", func(line string, i int) string {
+		return strconv.Itoa(i) + ":" + line
+	})
+	if got != "0:a\n1:b" {
+		t.Errorf("MapLines = %q", got)
+	}
+	got = purse.FilterLines("a\nb\nc\nd", func(_ string, i int) bool { return i%2 == 0 })
+	if got != "a\nc" {
+		t.Errorf("FilterLines = %q", got)
+	}
+	got = purse.FilterLines("keep\ndrop\nkeep too\nlast", func(line string, i int) bool {
+		return strings.HasPrefix(line, "keep") || i == 3
+	})
+	if got != "keep\nkeep too\nlast" {
+		t.Errorf("FilterLines = %q", got)
+	}
+	if got := purse.PrefixLines("a\n\nb", "> "); got != "> a\n> \n> b" {
+		t.Errorf("PrefixLines = %q", got)
+	}
+	if got := purse.RemoveEmptyLines("a\n \n\nb\n"); got != "a\nb" {
+		t.Errorf("RemoveEmptyLines = %q", got)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	inputs := []string{"plain", "it's a \"test\"", "a,b\nc", "<b>&</b>", " padded ", "tab\there ü", ""}
+	contexts := []purse.EscapeContext{purse.EscapeContextHTML, purse.EscapeContextShell, purse.EscapeContextCSV, purse.EscapeContextJSON}
+	for _, ctx := range contexts {
+		for _, in := range inputs {
+			escaped := purse.Escape(in, ctx)
+			back, err := purse.Unescape(escaped, ctx)
+			if err != nil || back != in {
+				t.Errorf("context %d: %q -> %q -> %q, %v", ctx, in, escaped, back, err)
+			}
+		}
+	}
+	if got := purse.EscapeShellArg("it's"); got != `'it'\''s'` {
+		t.Errorf("EscapeShellArg = %q", got)
+	}
+	if got := purse.EscapeCSVField(`say "hi"`); got != `"say ""hi"""` {
+		t.Errorf("EscapeCSVField = %q", got)
+	}
+	if got := purse.EscapeJSONString("a\"b\n<"); got != `a\"b\n<` {
+		t.Errorf("EscapeJSONString = %q", got)
+	}
+}
+
+func TestTransformOutside(t *testing.T) {
+	doc := "use  tabs\n```\nkeep  this\n```\nand  here"
+	got := purse.TransformOutside(doc, "```", "```", purse.NormalizeWhitespace)
+	if got != "use tabs\n```\nkeep  this\n```\nand here" {
+		t.Errorf("TransformOutside = %q", got)
+	}
+	got = purse.TransformOutside(`a "b" c "d`, `"`, `"`, strings.ToUpper)
+	if got != `A "b" C "d` {
+		t.Errorf("TransformOutside unclosed = %q", got)
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	paths := []string{"src/app/main.go", "src/app/util.go", "src/api.go"}
+	if got := purse.CommonPrefix(paths); got != "src/ap" {
+		t.Errorf("CommonPrefix = %q", got)
+	}
+	if got := purse.CommonSuffix(paths); got != ".go" {
+		t.Errorf("CommonSuffix = %q", got)
+	}
+	if got := purse.TrimCommonPrefix([]string{"getName", "getAge"}); got[0] != "Name" || got[1] != "Age" {
+		t.Errorf("TrimCommonPrefix = %q", got)
+	}
+	if got := purse.CommonPrefix([]string{"héllo", "hèllo"}); got != "h" {
+		t.Errorf("CommonPrefix split a rune: %q", got)
+	}
+	if got := purse.CommonPrefix([]string{"\xffabc", "\xffabd"}); got != "\xffab" {
+		t.Errorf("CommonPrefix with invalid UTF-8 = %q", got)
+	}
+	if got := purse.CommonPrefix([]string{"aé", "a\xc3"}); got != "a" {
+		t.Errorf("CommonPrefix split a rune in the first item = %q", got)
+	}
+}
+
+func TestRemoveDuplicatesFold(t *testing.T) {
+	got := purse.RemoveDuplicatesFold([]string{"Go", "GO", "rust", "go", "Rust", "Σίσυφος", "ΣΊΣΥΦΟΣ"})
+	if strings.Join(got, ",") != "Go,rust,Σίσυφος" {
+		t.Errorf("RemoveDuplicatesFold = %q", got)
+	}
+}
+
+func TestWindows(t *testing.T) {
+	w := purse.Windows([]int{1, 2, 3, 4}, 3)
+	if len(w) != 2 || w[1][0] != 2 || cap(w[0]) != 3 {
+		t.Errorf("Windows = %v", w)
+	}
+	if purse.Windows([]int{1}, 2) != nil {
+		t.Error("Windows larger than the slice should be nil")
+	}
+	if got := purse.NGrams("héllo", 2); strings.Join(got, ",") != "hé,él,ll,lo" {
+		t.Errorf("NGrams = %q", got)
+	}
+}
+
+func TestInsertSorted(t *testing.T) {
+	items := []string{"apple", "cherry"}
+	items = purse.InsertSorted(items, "banana")
+	items = purse.InsertSorted(items, "date")
+	if strings.Join(items, ",") != "apple,banana,cherry,date" {
+		t.Errorf("InsertSorted = %q", items)
+	}
+	if i, ok := purse.SearchSorted(items, "cherry"); !ok || i != 2 {
+		t.Errorf("SearchSorted = %d, %v", i, ok)
+	}
+	byLen := func(a, b string) int { return len(a) - len(b) }
+	words := purse.InsertSortedFunc([]string{"a", "abc"}, "ab", byLen)
+	if strings.Join(words, ",") != "a,ab,abc" {
+		t.Errorf("InsertSortedFunc = %q", words)
+	}
+}
+
+func TestSet(t *testing.T) {
+	s := purse.NewSet("c", "a", "b", "a")
+	s.Remove("a")
+	s.Add("a", "d")
+	if got := strings.Join(s.ToSlice(), ","); got != "c,b,a,d" || s.Len() != 4 {
+		t.Errorf("Set = %q", got)
+	}
+	other := purse.NewSet("d", "e", "c")
+	if got := strings.Join(s.Union(other).ToSlice(), ","); got != "c,b,a,d,e" {
+		t.Errorf("Union = %q", got)
+	}
+	if got := strings.Join(s.Intersect(other).ToSlice(), ","); got != "c,d" {
+		t.Errorf("Intersect = %q", got)
+	}
+	if got := strings.Join(s.Diff(other).ToSlice(), ","); got != "b,a" {
+		t.Errorf("Diff = %q", got)
+	}
+	for _, item := range []string{"c", "b", "a"} {
+		s.Remove(item)
+	}
+	if !s.Has("d") || s.Has("c") || strings.Join(s.ToSlice(), ",") != "d" {
+		t.Errorf("Set after removals = %q", s.ToSlice())
+	}
+}
+
+func TestReplaceFunc(t *testing.T) {
+	got := purse.ReplaceFunc("x x x x", "x", func(match string, i int) string {
+		if i == 2 {
+			return strings.ToUpper(match)
+		}
+		return match
+	})
+	if got != "x x X x" {
+		t.Errorf("ReplaceFunc = %q", got)
+	}
+	got = purse.ReplaceBetween("Hi {{ name }}, {{ greeting }}!", "{{", "}}", func(inner string) string {
+		return strings.ToUpper(strings.TrimSpace(inner))
+	})
+	if got != "Hi {{NAME}}, {{GREETING}}!" {
+		t.Errorf("ReplaceBetween = %q", got)
+	}
+}
+
+func TestFilterPrefix(t *testing.T) {
+	items := []string{"GetName", "getAge", "SetName", "name.go"}
+	if got := purse.FilterPrefix(items, "get"); strings.Join(got, ",") != "getAge" {
+		t.Errorf("FilterPrefix = %q", got)
+	}
+	if got := purse.FilterPrefix(items, "get", purse.IgnoreCase()); strings.Join(got, ",") != "GetName,getAge" {
+		t.Errorf("FilterPrefix IgnoreCase = %q", got)
+	}
+	if got := purse.FilterSuffix(items, "NAME", purse.IgnoreCase()); strings.Join(got, ",") != "GetName,SetName" {
+		t.Errorf("FilterSuffix = %q", got)
+	}
+	if got := purse.FilterContains(items, "name", purse.IgnoreCase()); len(got) != 3 {
+		t.Errorf("FilterContains = %q", got)
+	}
+}
+
+func TestLineEndings(t *testing.T) {
+	doc := "a\r\nb\r\nc\n"
+	if got := purse.DetectLineEnding(doc); got != "\r\n" {
+		t.Errorf("DetectLineEnding = %q", got)
+	}
+	if got := purse.DetectLineEnding("a\rb\rc"); got != "\r" {
+		t.Errorf("DetectLineEnding cr = %q", got)
+	}
+	if got := purse.NormalizeLineEndings("a\r\nb\rc\n", "\r\n"); got != "a\r\nb\r\nc\r\n" {
+		t.Errorf("NormalizeLineEndings = %q", got)
+	}
+	lines, ending := purse.SplitLines("x\r\ny\r\n")
+	if len(lines) != 3 || lines[0] != "x" || ending != "\r\n" {
+		t.Fatalf("SplitLines = %q, %q", lines, ending)
+	}
+	lines[1] = "Y"
+	if got := purse.JoinLinesWith(lines, ending); got != "x\r\nY\r\n" {
+		t.Errorf("JoinLinesWith = %q", got)
+	}
+	for _, target := range []string{"", " ", "\n\n"} {
+		if got := purse.NormalizeLineEndings("a\r\nb\n", target); got != "a\r\nb\n" {
+			t.Errorf("NormalizeLineEndings(target %q) = %q, want the input unchanged", target, got)
+		}
+	}
+}
+
+func TestCleanSlice(t *testing.T) {
+	input := strings.Split(" a, b ,, a ,c,  ", ",")
+	if got := purse.CleanSlice(input); strings.Join(got, "|") != "a|b|c" {
+		t.Errorf("CleanSlice = %q", got)
+	}
+	if got := purse.CleanSlice(input, purse.KeepDuplicates()); strings.Join(got, "|") != "a|b|a|c" {
+		t.Errorf("CleanSlice KeepDuplicates = %q", got)
+	}
+	if got := purse.CompactBlank(input); len(got) != 4 {
+		t.Errorf("CompactBlank = %q", got)
+	}
+}
+
+func TestFileLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := purse.WriteFileLines(path, []string{"b", "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := purse.TransformFile(path, func(s string) string {
+		return purse.PrefixLines(strings.TrimSuffix(s, "\n"), "- ") + "\n"
+	}); err != nil {
+		t.Fatal(err)
+	}
+	lines, err := purse.ReadFileLines(path)
+	if err != nil || strings.Join(lines, "|") != "- b|- a" {
+		t.Fatalf("ReadFileLines = %q, %v", lines, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Fatalf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestJoinWrapped(t *testing.T) {
+	items := []string{"alpha", "beta", "gamma", "delta", "epsilon"}
+	got := purse.JoinWrapped(items, ", ", 14)
+	if got != "alpha, beta,\ngamma, delta,\nepsilon" {
+		t.Errorf("JoinWrapped = %q", got)
+	}
+	if got := purse.JoinWrapped([]string{"toolongitem", "x"}, " ", 4); got != "toolongitem\nx" {
+		t.Errorf("JoinWrapped long item = %q", got)
+	}
+	if got := purse.JoinWrapped([]string{"aa", "bb", "cc"}, ", ", 6); got != "aa,\nbb, cc" {
+		t.Errorf("JoinWrapped counting the separator = %q", got)
+	}
+	if got := purse.JoinWrapped([]string{"a", "b", "c", "d"}, " | ", 6); got != "a |\nb |\nc | d" {
+		t.Errorf("JoinWrapped with a non-space separator = %q", got)
+	}
+	items = []string{"one", "two", "three", "four", "five", "six"}
+	for width := 6; width <= 20; width++ {
+		for _, line := range purse.MakeLines(purse.JoinWrapped(items, "; ", width)) {
+			if purse.VisualWidth(line) > width {
+				t.Errorf("JoinWrapped(width %d) line %q is too wide", width, line)
+			}
+		}
+	}
+	// An item that only fits without its separator is oversized and keeps a line to itself
+	if got := purse.JoinWrapped([]string{"aaaa", "bbbb"}, ", ", 4); got != "aaaa,\nbbbb" {
+		t.Errorf("JoinWrapped oversized item = %q", got)
+	}
+}
+
+func TestRegexFromLiterals(t *testing.T) {
+	pattern := purse.RegexFromLiterals([]string{"foo", "foo.bar", "fo", "foo", ""})
+	if pattern != `(?:foo\.bar|foo|fo)` {
+		t.Errorf("RegexFromLiterals = %q", pattern)
+	}
+	re := regexp.MustCompile(pattern)
+	if got := re.FindAllString("foo.bar fooxbar fo", -1); strings.Join(got, ",") != "foo.bar,foo,fo" {
+		t.Errorf("matches = %q", got)
+	}
+	if regexp.MustCompile(purse.RegexFromLiterals(nil)).MatchString("anything") {
+		t.Error("empty literal list matched")
+	}
+}
+
+func TestPathClassification(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+	if !purse.PathExists(file) || purse.PathExists(missing) {
+		t.Error("PathExists")
+	}
+	if !purse.IsFile(file) || purse.IsFile(dir) || purse.IsFile(missing) {
+		t.Error("IsFile")
+	}
+	if !purse.IsDir(dir) || purse.IsDir(file) {
+		t.Error("IsDir")
+	}
+	for _, s := range []string{"src/main.go", "./run", "~/notes", `C:\temp`, "README.md"} {
+		if !purse.LooksLikePath(s) {
+			t.Errorf("LooksLikePath(%q) = false", s)
+		}
+	}
+	for _, s := range []string{"hello", "https://example.com/x", "", "two words.txt", "v1.2.3-beta!"} {
+		if purse.LooksLikePath(s) {
+			t.Errorf("LooksLikePath(%q) = true", s)
+		}
+	}
+}
+
+func TestCodeWriter(t *testing.T) {
+	w := purse.NewCodeWriter("\t")
+	w.Line("package %s", "main")
+	w.Line("")
+	w.Block("func main() {", "}", func() {
+		w.Block("for i := 0; i < %d; i++ {", "}", func() {
+			w.Line("fmt.Println(i)")
+		})
+		w.Line("fmt.Println(\"%d%%\")", 100)
+	})
+	want := "package main\n\nfunc main() {\n\tfor i := 0; i < %d; i++ {\n\t\tfmt.Println(i)\n\t}\n\tfmt.Println(\"100%\")\n}\n"
+	if got := w.String(); got != want {
+		t.Errorf("CodeWriter =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	plurals := map[string]string{"file": "files", "box": "boxes", "city": "cities", "day": "days", "Child": "Children", "match": "matches"}
+	for word, want := range plurals {
+		if got := purse.Pluralize(word, 2); got != want {
+			t.Errorf("Pluralize(%q) = %q, want %q", word, got, want)
+		}
+	}
+	if got := purse.Pluralize("file", 1); got != "file" {
+		t.Errorf("Pluralize singular = %q", got)
+	}
+	ordinals := map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 11: "11th", 12: "12th", 13: "13th", 21: "21st", 102: "102nd", 111: "111th"}
+	for n, want := range ordinals {
+		if got := purse.Ordinal(n); got != want {
+			t.Errorf("Ordinal(%d) = %q, want %q", n, got, want)
+		}
+	}
+	bytes := map[int64]string{512: "512 B", 1024: "1 KiB", 1536: "1.5 KiB", 5 << 20: "5 MiB", -2048: "-2 KiB", 1048575: "1 MiB", 1023: "1023 B", math.MinInt64: "-8 EiB"}
+	for n, want := range bytes {
+		if got := purse.HumanizeBytes(n); got != want {
+			t.Errorf("HumanizeBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+	durations := map[time.Duration]string{
+		250 * time.Millisecond:         "250ms",
+		45 * time.Second:               "45s",
+		3*time.Minute + 20*time.Second: "3m 20s",
+		2*time.Hour + 5*time.Second:    "2h",
+		26 * time.Hour:                 "1d 2h",
+	}
+	for d, want := range durations {
+		if got := purse.HumanizeDuration(d); got != want {
+			t.Errorf("HumanizeDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestSplitIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"parseHTTP2Frames": "parse|HTTP|2|Frames",
+		"HTTPServer":       "HTTP|Server",
+		"user_id-list":     "user|id|list",
+		"XMLHttpRequest":   "XML|Http|Request",
+		"v2api":            "v|2|api",
+		"":                 "",
+	}
+	for in, want := range tests {
+		if got := strings.Join(purse.SplitIdentifier(in), "|"); got != want {
+			t.Errorf("SplitIdentifier(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := purse.ToSnakeCase("parseHTTP2Frames"); got != "parse_http_2_frames" {
+		t.Errorf("ToSnakeCase = %q", got)
+	}
+	if got := purse.ToCamelCase("user-id_list"); got != "userIdList" {
+		t.Errorf("ToCamelCase = %q", got)
+	}
+	if got := purse.ToPascalCase("http server"); got != "HttpServer" {
+		t.Errorf("ToPascalCase = %q", got)
+	}
+	if got := purse.ToKebabCase("HTTPServer"); got != "http-server" {
+		t.Errorf("ToKebabCase = %q", got)
+	}
+	for in, want := range map[string]string{
+		"hx-get-URL": "hxGetUrl",
+		"-foo-bar":   "FooBar",
+		"foo-2d":     "foo2d",
+		"fooBar-baz": "fooBarBaz",
+		"a--b":       "aB",
+	} {
+		if got := purse.KebabToCamelCase(in); got != want {
+			t.Errorf("KebabToCamelCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFindDuplicateLines(t *testing.T) {
+	input := "a = 1\nb = 2\n\na = 1\n  b  =  2\n\na = 1"
+	got := purse.FindDuplicateLines(input)
+	if len(got) != 1 || got[0].Text != "a = 1" || fmt.Sprint(got[0].Lines) != "[1 4 7]" {
+		t.Errorf("FindDuplicateLines = %+v", got)
+	}
+	got = purse.FindDuplicateLines(input, purse.IgnoreWhitespace())
+	if len(got) != 2 || got[1].Text != "b = 2" || fmt.Sprint(got[1].Lines) != "[2 5]" {
+		t.Errorf("FindDuplicateLines(IgnoreWhitespace) = %+v", got)
+	}
+	if got := purse.FindDuplicateLines("x\ny"); len(got) != 0 {
+		t.Errorf("FindDuplicateLines without duplicates = %+v", got)
+	}
+}
+
+func TestHash(t *testing.T) {
+	if purse.Hash("") != 0xcbf29ce484222325 {
+		t.Errorf("Hash(\"\") = %x, want the FNV-1a offset basis", purse.Hash(""))
+	}
+	if purse.Hash("a") == purse.Hash("b") {
+		t.Error("Hash collided on distinct inputs")
+	}
+	full := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := purse.ShortHash("abc", 8); got != full[:8] {
+		t.Errorf("ShortHash(abc, 8) = %q", got)
+	}
+	if got := purse.ShortHash("abc", 0); got != full {
+		t.Errorf("ShortHash(abc, 0) = %q", got)
+	}
+	if got := purse.ShortHash("abc", 100); got != full {
+		t.Errorf("ShortHash(abc, 100) = %q", got)
+	}
+	a := purse.ContentFingerprint([]string{"ab", "c"})
+	b := purse.ContentFingerprint([]string{"a", "bc"})
+	if a == b || len(a) != 64 {
+		t.Errorf("ContentFingerprint = %q, %q", a, b)
+	}
+	if a != purse.ContentFingerprint([]string{"ab", "c"}) {
+		t.Error("ContentFingerprint is not deterministic")
+	}
+}
+
+func TestLinePrefixPredicates(t *testing.T) {
+	quoted := "> one\n> two\n> three"
+	mixed := "# title\ntext\n# other"
+	if !purse.AllLinesHavePrefix(quoted, "> ") || purse.AllLinesHavePrefix(mixed, "#") {
+		t.Error("AllLinesHavePrefix gave the wrong answer")
+	}
+	if !purse.AnyLineHasPrefix(mixed, "text") || purse.AnyLineHasPrefix(quoted, "#") {
+		t.Error("AnyLineHasPrefix gave the wrong answer")
+	}
+	if got := fmt.Sprint(purse.LinesWithPrefix(mixed, "#")); got != "[0 2]" {
+		t.Errorf("LinesWithPrefix = %s", got)
+	}
+}
+
+func TestDecodeEscapes(t *testing.T) {
+	got, err := purse.DecodeEscapes(`tab\there\nnew \x41é\\ "q" \'s\'`)
+	if err != nil || got != "tab\there\nnew Aé\\ \"q\" 's'" {
+		t.Errorf("DecodeEscapes = %q, %v", got, err)
+	}
+	if _, err := purse.DecodeEscapes(`bad \q`); err == nil {
+		t.Error("DecodeEscapes accepted an unknown escape")
+	}
+	if _, err := purse.DecodeEscapes(`trailing \`); err == nil {
+		t.Error("DecodeEscapes accepted a trailing backslash")
+	}
+	for _, s := range []string{"plain", "a\tb\nc\\d", "é \x00\x7f\xff\u200b"} {
+		encoded := purse.EncodeEscapes(s)
+		decoded, err := purse.DecodeEscapes(encoded)
+		if err != nil || decoded != s {
+			t.Errorf("round trip of %q via %q = %q, %v", s, encoded, decoded, err)
+		}
+	}
+	if got := purse.EncodeEscapes("a\nb\té\x01"); got != `a\nb\té\x01` {
+		t.Errorf("EncodeEscapes = %q", got)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	if got := purse.Mask("sk-12345678", 3, 2, '*'); got != "sk-******78" {
+		t.Errorf("Mask = %q", got)
+	}
+	if got := purse.Mask("abc", 2, 2, '#'); got != "###" {
+		t.Errorf("Mask of a short secret = %q", got)
+	}
+	if got := purse.Mask("pässwörd", 1, -1, '•'); got != "p•••••••" {
+		t.Errorf("Mask with runes = %q", got)
+	}
+	if got := purse.RedactBetween(`token="abc" user="bob"`, `token="`, `"`, "[REDACTED]"); got != `token="[REDACTED]" user="bob"` {
+		t.Errorf("RedactBetween = %q", got)
+	}
+	lines := purse.MakeLines("auth hunter2\nkey hunter22 and hunter2\nnothing")
+	for i, line := range lines {
+		lines[i] = purse.RedactPattern(line, []string{"hunter2", "hunter22", ""}, "***")
+	}
+	if got := purse.JoinLines(lines); got != "auth ***\nkey *** and ***\nnothing" {
+		t.Errorf("RedactPattern = %q", got)
+	}
+}
+
+// benchDocument is a large indented document with blank lines, shared by the line benchmarks.
+var benchDocument = strings.Repeat("    func main() {\n\n\t\tfmt.Println(\"hello\")   \n  }\n", 20000)
+
+func BenchmarkPrefixLines(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		purse.PrefixLines(benchDocument, "// ")
+	}
+}
+
+func BenchmarkRemoveEmptyLines(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		purse.RemoveEmptyLines(benchDocument)
+	}
+}
+
+func BenchmarkTrimLeadingSpaces(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		purse.TrimLeadingSpaces(benchDocument)
+	}
+}
+
+func BenchmarkTrimTrailingSpaces(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		purse.TrimTrailingSpaces(benchDocument)
+	}
+}
+
+func BenchmarkFlatten(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		purse.Flatten(benchDocument)
+	}
+}
+
+func BenchmarkDiffSlices(b *testing.B) {
+	want := diffBenchLines(5000)
+	got := append([]string(nil), want...)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		j, k := r.Intn(len(got)), r.Intn(len(got))
+		got[j], got[k] = got[k], got[j]
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		purse.DiffSlices(want, got)
+	}
+}
+
+func TestSinglePassLineTransforms(t *testing.T) {
+	identity := func(line string, _ int) string { return line }
+	keepAll := func(_ string, _ int) bool { return true }
+	for _, s := range []string{"", "\n", "a\n", "\na", "a\n\nb", "  x\n\t y\n"} {
+		want := purse.JoinLines(purse.MakeLines(s))
+		if got := purse.MapLines(s, identity); got != want {
+			t.Errorf("MapLines(%q) = %q", s, got)
+		}
+		if got := purse.FilterLines(s, keepAll); got != want {
+			t.Errorf("FilterLines(%q) = %q", s, got)
+		}
+	}
+	if got := purse.PrefixLines("a\n\nb\n", "> "); got != "> a\n> \n> b\n> " {
+		t.Errorf("PrefixLines = %q", got)
+	}
+	if got := purse.Flatten("  a\n\tb\n c"); got != "abc" {
+		t.Errorf("Flatten = %q", got)
+	}
+	if got := purse.RemoveEmptyLines("\n\na\n \nb\n"); got != "a\nb" {
+		t.Errorf("RemoveEmptyLines = %q", got)
+	}
+}
+
+func TestLineSink(t *testing.T) {
+	var got []string
+	sink := purse.NewLineSink(func(line string) {
+		got = append(got, line)
+	})
+	for _, chunk := range []string{"fir", "st\nsec", "ond\n\nthi", "rd"} {
+		if n, err := fmt.Fprint(sink, chunk); err != nil || n != len(chunk) {
+			t.Fatalf("Write(%q) = %d, %v", chunk, n, err)
+		}
+	}
+	if strings.Join(got, "|") != "first|second|" {
+		t.Errorf("lines before Flush = %q", got)
+	}
+	sink.Flush()
+	sink.Flush()
+	if strings.Join(got, "|") != "first|second||third" {
+		t.Errorf("lines after Flush = %q", got)
+	}
+}
+
+func TestTryVariants(t *testing.T) {
+	got, err := purse.TryReplaceLastSubStr("a-b-c", "-", "+")
+	if err != nil || got != "a-b+c" {
+		t.Errorf("TryReplaceLastSubStr = %q, %v", got, err)
+	}
+	if _, err := purse.TryReplaceLastSubStr("abc", "x", "y"); !errors.Is(err, purse.ErrNotFound) {
+		t.Errorf("TryReplaceLastSubStr error = %v", err)
+	}
+	if got, err := purse.TryReplaceFirstInstanceOf("a-b-c", "-", "+"); err != nil || got != "a+b-c" {
+		t.Errorf("TryReplaceFirstInstanceOf = %q, %v", got, err)
+	}
+	if _, err := purse.TryReplaceLastInstanceOf("abc", "x", "y"); !errors.Is(err, purse.ErrNotFound) {
+		t.Errorf("TryReplaceLastInstanceOf error = %v", err)
+	}
+	if got, err := purse.TryTargetSearch("x <a> y", "<", ">"); err != nil || got != "<a>" {
+		t.Errorf("TryTargetSearch = %q, %v", got, err)
+	}
+	if _, err := purse.TryTargetSearch("x <a y", "<", ">"); err == nil || !strings.Contains(err.Error(), "secondary") {
+		t.Errorf("TryTargetSearch error = %v", err)
+	}
+	if _, err := purse.TryReplaceFirstLine("", "x"); !errors.Is(err, purse.ErrNotFound) {
+		t.Errorf("TryReplaceFirstLine error = %v", err)
+	}
+	if got, err := purse.TryReplaceLastLine("a\nb", "c"); err != nil || got != "a\nc" {
+		t.Errorf("TryReplaceLastLine = %q, %v", got, err)
+	}
+	if got := purse.Must(purse.TryReplaceFirstLine("a\nb", "c")); got != "c\nb" {
+		t.Errorf("Must = %q", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Must did not panic on an error")
+		}
+	}()
+	purse.Must(purse.TryReplaceLastSubStr("abc", "x", "y"))
+}
+
+func TestCappedBuffer(t *testing.T) {
+	buf := purse.NewCappedBuffer(2, 2)
+	for i := 1; i <= 6; i++ {
+		buf.Append(fmt.Sprintf("line %d\n", i))
+	}
+	buf.Append("partial")
+	want := "line 1\nline 2\n... 2 lines omitted ...\nline 5\nline 6\npartial"
+	if got := buf.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if buf.Omitted() != 2 {
+		t.Errorf("Omitted() = %d", buf.Omitted())
+	}
+
+	small := purse.NewCappedBuffer(1, 10, purse.MaxBytes(12))
+	fmt.Fprint(small, "aaaa\nbbbb\ncc")
+	fmt.Fprint(small, "cc\n")
+	if got := small.String(); got != "aaaa\n... 1 line omitted ...\ncccc\n" {
+		t.Errorf("String() with MaxBytes = %q", got)
+	}
+
+	// Once a line skips the head, later short lines must not jump ahead of it
+	ordered := purse.NewCappedBuffer(2, 2, purse.MaxBytes(10))
+	ordered.Append("a\nbbbbbbbbbbbb\nc\nd\n")
+	if got := ordered.String(); got != "a\n... 1 line omitted ...\nc\nd\n" {
+		t.Errorf("String() after an oversized line = %q", got)
+	}
+
+	// The pending partial line counts toward MaxBytes and is cut once it cannot fit
+	pending := purse.NewCappedBuffer(1, 5, purse.MaxBytes(10))
+	pending.Append("ab\ncd\nefghij")
+	if got := pending.String(); got != "ab\n... 1 line omitted ...\nefghij" {
+		t.Errorf("String() with a partial line = %q", got)
+	}
+	pending.Append("klmnop")
+	if got := pending.String(); got != "ab\n... 1 line omitted ...\nefghijk" {
+		t.Errorf("String() with an oversized partial line = %q", got)
+	}
+	pending.Append("\nz\n")
+	if got := pending.String(); got != "ab\n... 2 lines omitted ...\nz\n" {
+		t.Errorf("String() after an oversized partial line = %q", got)
+	}
+
+	long := purse.NewCappedBuffer(1, 1)
+	for i := 0; i < 100; i++ {
+		long.Append(strings.Repeat("x", 1<<10))
+	}
+	if got := len(long.String()); got != 64<<10 {
+		t.Errorf("partial line without MaxBytes kept %d bytes", got)
+	}
+	long.Append("\n")
+	if got := long.String(); got != strings.Repeat("x", 64<<10)+"\n" {
+		t.Errorf("long line without MaxBytes kept %d bytes", len(got))
+	}
+
+	whole := purse.NewCappedBuffer(5, 5)
+	whole.Append("a\nb\n")
+	if got := whole.String(); got != "a\nb\n" {
+		t.Errorf("String() under the cap = %q", got)
+	}
+}
+
+func TestDelimitedLines(t *testing.T) {
+	fields, err := purse.SplitCSVLine(`a,"b, c","say ""hi""",,"multi
+line"`)
+	if err != nil || strings.Join(fields, "|") != "a|b, c|say \"hi\"||multi\nline" {
+		t.Errorf("SplitCSVLine = %q, %v", fields, err)
+	}
+	if _, err := purse.SplitCSVLine(`a,"unterminated`); err == nil {
+		t.Error("SplitCSVLine accepted an unterminated quote")
+	}
+	if _, err := purse.SplitCSVLine("a,b\nc,d"); err == nil {
+		t.Error("SplitCSVLine accepted two records")
+	}
+	if fields, err := purse.SplitCSVLine(""); err != nil || len(fields) != 1 || fields[0] != "" {
+		t.Errorf("SplitCSVLine(\"\") = %q, %v", fields, err)
+	}
+	row := []string{"plain", "with,comma", `with "quote"`, ""}
+	joined := purse.JoinCSVLine(row)
+	if joined != `plain,"with,comma","with ""quote""",` {
+		t.Errorf("JoinCSVLine = %q", joined)
+	}
+	if back, err := purse.SplitCSVLine(joined); err != nil || strings.Join(back, "|") != strings.Join(row, "|") {
+		t.Errorf("CSV round trip = %q, %v", back, err)
+	}
+	tsv := purse.JoinTSVLine([]string{"a", "b\tc", "d,e"})
+	if tsv != "a\t\"b\tc\"\td,e" {
+		t.Errorf("JoinTSVLine = %q", tsv)
+	}
+	if back, err := purse.SplitTSVLine(tsv); err != nil || len(back) != 3 || back[1] != "b\tc" {
+		t.Errorf("SplitTSVLine = %q, %v", back, err)
+	}
+}
+
+func TestHeredoc(t *testing.T) {
+	got := purse.Heredoc(`
+		first
+		  second
+
+		third
+	`)
+	if got != "first\n  second\n\nthird\n" {
+		t.Errorf("Heredoc = %q", got)
+	}
+	if got := purse.Heredoc("no leading blank\n  x"); got != "no leading blank\n  x" {
+		t.Errorf("Heredoc without indentation = %q", got)
+	}
+	got = purse.Heredocf(`
+		name: %s
+		count: %d
+	`, "purse", 3)
+	if got != "name: purse\ncount: 3\n" {
+		t.Errorf("Heredocf = %q", got)
+	}
+	if got := purse.Dedent("\t\ta\n\t\t\tb\n \n\t\tc"); got != "a\n\tb\n\nc" {
+		t.Errorf("Dedent = %q", got)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	rec := purse.NewRecorder()
+	s := "  a\n\n  b\n\n  c"
+	s = rec.Apply("RemoveEmptyLines", s, purse.RemoveEmptyLines)
+	s = rec.Apply("TrimLeadingSpaces", s, purse.TrimLeadingSpaces)
+	s = rec.Apply("StripFinalNewline", s, purse.StripFinalNewline)
+	if s != "a\nb\nc" {
+		t.Fatalf("Apply result = %q", s)
+	}
+	want := "1. RemoveEmptyLines: 5 -> 3 lines (+0 -2)\n" +
+		"2. TrimLeadingSpaces: 3 -> 3 lines (+3 -3)\n" +
+		"3. StripFinalNewline: unchanged"
+	if got := rec.Changelog(); got != want {
+		t.Errorf("Changelog() =\n%s\nwant\n%s", got, want)
+	}
+	if got := rec.Replay("    x\n\n y"); got != "x\ny" {
+		t.Errorf("Replay = %q", got)
+	}
+	if steps := rec.Steps(); len(steps) != 3 || steps[0].Removed != 2 || steps[2].Changed {
+		t.Errorf("Steps() = %+v", steps)
+	}
+}
+
+func TestJoinNonEmpty(t *testing.T) {
+	if got := purse.JoinNonEmpty(" - ", "a", "", "  ", "b"); got != "a - b" {
+		t.Errorf("JoinNonEmpty = %q", got)
+	}
+	if got := purse.JoinNonEmpty(", "); got != "" {
+		t.Errorf("JoinNonEmpty() = %q", got)
+	}
+	if got := purse.ConcatNonEmpty("x", " ", "y ", ""); got != "xy " {
+		t.Errorf("ConcatNonEmpty = %q", got)
+	}
+}
+
+func TestRandSource(t *testing.T) {
+	a, b := purse.NewRandSource(42), purse.NewRandSource(42)
+	if a.Paragraph(3) != b.Paragraph(3) || a.Base62(16) != b.Base62(16) {
+		t.Error("sources with the same seed diverged")
+	}
+	if hex := a.Hex(32); !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(hex) {
+		t.Errorf("Hex(32) = %q", hex)
+	}
+	if s := a.Base62(20); !regexp.MustCompile(`^[0-9A-Za-z]{20}$`).MatchString(s) {
+		t.Errorf("Base62(20) = %q", s)
+	}
+	if w := a.Word(); w == "" || strings.ContainsAny(w, " .") {
+		t.Errorf("Word() = %q", w)
+	}
+	lorem := a.LoremIpsum(40)
+	if !strings.HasPrefix(lorem, "Lorem ipsum dolor sit amet") || !strings.HasSuffix(lorem, ".") {
+		t.Errorf("LoremIpsum(40) = %q", lorem)
+	}
+	if n := len(strings.Fields(lorem)); n != 40 {
+		t.Errorf("LoremIpsum(40) has %d words", n)
+	}
+	if got := a.LoremIpsum(2); got != "Lorem ipsum." {
+		t.Errorf("LoremIpsum(2) = %q", got)
+	}
+	if got := strings.Count(a.Paragraph(4), "."); got != 4 {
+		t.Errorf("Paragraph(4) has %d sentences", got)
+	}
+	if a.Hex(0) != "" || a.Sentence(0) != "" || a.Paragraph(0) != "" {
+		t.Error("zero-length generators returned text")
+	}
+	purse.SetRandSource(rand.NewSource(5))
+	first := purse.RandHex(8) + purse.RandBase62(8) + purse.RandWord() + purse.LoremIpsum(10) + purse.RandParagraph(2)
+	purse.SetRandSource(rand.NewSource(5))
+	second := purse.RandHex(8) + purse.RandBase62(8) + purse.RandWord() + purse.LoremIpsum(10) + purse.RandParagraph(2)
+	if first != second {
+		t.Error("package helpers ignored SetRandSource")
+	}
+}
+
+// recordingT captures assertion failures instead of failing the enclosing test.
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestApproximateLineAssertions(t *testing.T) {
+	if !purse.LinesEqualIgnoringWhitespace("func f() {\n\treturn  1\n}\n", "  func f()   {\n\n    return 1\n}") {
+		t.Error("LinesEqualIgnoringWhitespace rejected whitespace-only differences")
+	}
+	if purse.LinesEqualIgnoringWhitespace("a b\nc", "a\nb c") {
+		t.Error("LinesEqualIgnoringWhitespace accepted moved line breaks")
+	}
+	if got := purse.DiffIgnoringIndentation("a\n\tb\nc", "  a\n    b\nc"); got != "" {
+		t.Errorf("DiffIgnoringIndentation of equal text = %q", got)
+	}
+	got := purse.DiffIgnoringIndentation("a\nb\nc\nd", "a\n  x\nc\nd\ne")
+	if got != "-2: b\n+2:   x\n+5: e" {
+		t.Errorf("DiffIgnoringIndentation = %q", got)
+	}
+	rec := &recordingT{}
+	doc := "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}"
+	if !purse.AssertContainsLine(rec, doc, "func main() {") || len(rec.errors) != 0 {
+		t.Errorf("AssertContainsLine failed on a present line: %v", rec.errors)
+	}
+	if purse.AssertContainsLine(rec, doc, `println("bye")`) {
+		t.Error("AssertContainsLine passed on a missing line")
+	}
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "closest is line 4") {
+		t.Errorf("AssertContainsLine message = %v", rec.errors)
+	}
+}
+
+func TestJSONFormatting(t *testing.T) {
+	doc := `{"b": [1, 2.50], "a": {"y": "<tag>", "x": null}}`
+	pretty, err := purse.PrettyJSON(doc, "  ")
+	want := "{\n  \"b\": [\n    1,\n    2.50\n  ],\n  \"a\": {\n    \"y\": \"<tag>\",\n    \"x\": null\n  }\n}"
+	if err != nil || pretty != want {
+		t.Errorf("PrettyJSON = %q, %v", pretty, err)
+	}
+	compact, err := purse.CompactJSON(doc)
+	if err != nil || compact != `{"b":[1,2.50],"a":{"y":"<tag>","x":null}}` {
+		t.Errorf("CompactJSON = %q, %v", compact, err)
+	}
+	sorted, err := purse.CompactJSON(doc, purse.SortKeys())
+	if err != nil || sorted != `{"a":{"x":null,"y":"<tag>"},"b":[1,2.50]}` {
+		t.Errorf("CompactJSON(SortKeys) = %q, %v", sorted, err)
+	}
+	sortedPretty, err := purse.PrettyJSON(`{"b": 1, "a": 2}`, "  ", purse.SortKeys())
+	if err != nil || sortedPretty != "{\n  \"a\": 2,\n  \"b\": 1\n}" {
+		t.Errorf("PrettyJSON(SortKeys) = %q, %v", sortedPretty, err)
+	}
+	if _, err := purse.PrettyJSON(`{"a":`, "\t"); err == nil {
+		t.Error("PrettyJSON accepted invalid JSON")
+	}
+	if _, err := purse.CompactJSON(`{"a":`, purse.SortKeys()); err == nil {
+		t.Error("CompactJSON(SortKeys) accepted invalid JSON")
+	}
+	if _, err := purse.CompactJSON(`{} {}`, purse.SortKeys()); err == nil {
+		t.Error("CompactJSON(SortKeys) accepted trailing data")
+	}
+}
+
+func TestBalanced(t *testing.T) {
+	balanced := []string{"", "f(a[1], {b: 2})", `x := "(" + ")"`, "`{` {}", "it's (fine)"}
+	for _, s := range balanced {
+		if !purse.IsBalanced(s) {
+			t.Errorf("IsBalanced(%q) = false", s)
+		}
+	}
+	unbalanced := []string{"(]", "((", "}", `"unterminated (`, "f(a[1)]"}
+	for _, s := range unbalanced {
+		if purse.IsBalanced(s) {
+			t.Errorf("IsBalanced(%q) = true", s)
+		}
+	}
+	if !purse.IsBalanced("{{ if }}{{ end }}", [2]string{"{{", "}}"}) || purse.IsBalanced("{{ x }", [2]string{"{{", "}}"}) {
+		t.Error("IsBalanced with custom pairs gave the wrong answer")
+	}
+
+	src := "func f() {\n\tif x { s := \"}\" }\n}\nfunc g() {}"
+	inner, rest, err := purse.ExtractBalanced(src, "{", "}")
+	if err != nil || inner != "\n\tif x { s := \"}\" }\n" || rest != "\nfunc g() {}" {
+		t.Errorf("ExtractBalanced = %q, %q, %v", inner, rest, err)
+	}
+	if _, _, err := purse.ExtractBalanced("no braces", "{", "}"); err == nil {
+		t.Error("ExtractBalanced found a missing delimiter")
+	}
+	if _, _, err := purse.ExtractBalanced(`{"a": {"b": 1}`, "{", "}"); err == nil || !strings.Contains(err.Error(), "offset 0") {
+		t.Errorf("ExtractBalanced of an unclosed object: %v", err)
+	}
+
+	body := "func f() {\n\t// don't panic\n\treturn\n}"
+	inner, _, err = purse.ExtractBalanced(body, "{", "}")
+	if err != nil || inner != "\n\t// don't panic\n\treturn\n" {
+		t.Errorf("ExtractBalanced with an apostrophe in a comment = %q, %v", inner, err)
+	}
+
+	code := "f(')', \"(\") // don't ( \n/* ) */ g()"
+	if purse.IsBalanced(code) || !purse.CodeBalancer.IsBalanced(code) {
+		t.Error("CodeBalancer should skip rune literals and comments that DefaultBalancer reads")
+	}
+	inner, rest, err = purse.CodeBalancer.Extract("{ r := '}' // it's }\n} tail", "{", "}")
+	if err != nil || inner != " r := '}' // it's }\n" || rest != " tail" {
+		t.Errorf("CodeBalancer.Extract = %q, %q, %v", inner, rest, err)
+	}
+	custom := purse.Balancer{Pairs: [][2]string{{"<", ">"}}, Quotes: "'"}
+	if !custom.IsBalanced("<a '>' b>") || custom.IsBalanced("<a") {
+		t.Error("custom Balancer gave the wrong answer")
+	}
+}
+
+func TestCheckBalanced(t *testing.T) {
+	if ok, errs := purse.CheckBalanced("f(a[1], {b: \"}\"})", nil); !ok || len(errs) != 0 {
+		t.Errorf("CheckBalanced of balanced input = %v, %v", ok, errs)
+	}
+	ok, errs := purse.CheckBalanced("x)\n{ (a]\n[", nil)
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`1:2: unexpected ")"`,
+		`2:5: unexpected "]"`,
+		`2:1: "{" is never closed`,
+		`2:3: "(" is never closed`,
+		`3:1: "[" is never closed`,
+	}
+	if ok || strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("CheckBalanced errors =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	_, errs = purse.CheckBalanced("{ ( }", nil)
+	if len(errs) != 1 || errs[0].Offset != 4 || !strings.Contains(errs[0].Msg, `before "("`) {
+		t.Errorf("CheckBalanced of a crossed close = %v", errs)
+	}
+	_, errs = purse.CheckBalanced("{{ if }}{{ x }", map[string]string{"{{": "}}", "{": "}"})
+	if len(errs) != 2 || errs[0].Delim != "}" || errs[1].Delim != "{{" || errs[1].Col != 9 {
+		t.Errorf("CheckBalanced with template pairs = %v", errs)
+	}
+	if ok, errs := purse.CheckBalanced("Don't forget {x}, it's (really) [important]", nil); !ok || len(errs) != 0 {
+		t.Errorf("CheckBalanced of prose = %v, %v", ok, errs)
+	}
+	sq := purse.Balancer{Pairs: [][2]string{{"{", "}"}}, Quotes: `"'`}
+	if ok, errs := sq.Check("{'}'}"); !ok || len(errs) != 0 {
+		t.Errorf("Check with single quotes = %v, %v", ok, errs)
+	}
+	if ok, errs := sq.Check("Don't {x}"); ok || len(errs) != 1 || errs[0].Msg != "unterminated string literal" {
+		t.Errorf("Check of prose with single quotes = %v, %v", ok, errs)
+	}
+	_, errs = purse.CheckBalanced(`say "hi`, nil)
+	if len(errs) != 1 || errs[0].Msg != "unterminated string literal" || errs[0].Offset != 4 {
+		t.Errorf("CheckBalanced of an unterminated quote = %v", errs)
+	}
+}
+
+func TestTrimSomeLeadingTabs(t *testing.T) {
+	in := "\t\t\ta\n\tb\nc\n\t\t\t\n\t \td"
+	if got := purse.TrimSomeLeadingTabs(in, 2); got != "\ta\nb\nc\n\n \td" {
+		t.Errorf("TrimSomeLeadingTabs = %q", got)
+	}
+	if got := purse.TrimSomeLeadingTabs(in, 0); got != in {
+		t.Errorf("TrimSomeLeadingTabs(0) = %q", got)
+	}
+}
+
+func TestLintWhitespace(t *testing.T) {
+	doc := "func f() {\n\tx := 1 \n    y := 2\n \tz := 3\n\t\t// aligned  \n}"
+	var got []string
+	for _, issue := range purse.LintWhitespace(doc) {
+		got = append(got, fmt.Sprintf("%d:%s", issue.Line, issue.Msg))
+	}
+	want := []string{
+		"2:trailing whitespace",
+		"3:indented with spaces in a tab-indented document",
+		"4:tab after space in indentation",
+		"5:trailing whitespace",
+		"6:missing final newline",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("LintWhitespace =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	fixed := purse.FixWhitespace(doc)
+	if fixed != "func f() {\n\tx := 1\n\ty := 2\n\tz := 3\n\t\t// aligned\n}\n" {
+		t.Errorf("FixWhitespace = %q", fixed)
+	}
+	if issues := purse.LintWhitespace(fixed); len(issues) != 0 {
+		t.Errorf("LintWhitespace after FixWhitespace = %+v", issues)
+	}
+	if got := purse.FixWhitespace("a\n\tb\n", purse.IndentWith("  "), purse.TabWidth(2)); got != "a\n  b\n" {
+		t.Errorf("FixWhitespace(IndentWith) = %q", got)
+	}
+	if purse.FixWhitespace("") != "" || len(purse.LintWhitespace("")) != 0 {
+		t.Error("empty input should need no fixes")
+	}
+}
+
+func TestBreakOpportunities(t *testing.T) {
+	got := purse.InsertBreakOpportunities("see https://example.com/abc  ok", 8, "<wbr>")
+	if got != "see https://<wbr>example.<wbr>com/abc  ok" {
+		t.Errorf("InsertBreakOpportunities = %q", got)
+	}
+	if got := purse.InsertBreakOpportunities("日本語テキスト", 4, "\u200b"); got != "日本\u200b語テ\u200bキス\u200bト" {
+		t.Errorf("InsertBreakOpportunities with wide runes = %q", got)
+	}
+	if got := purse.HyphenateLongWords("hash 0123456789a end", 4); got != "hash 012- 345- 678- 9a end" {
+		t.Errorf("HyphenateLongWords = %q", got)
+	}
+	if got := purse.HyphenateLongWords("abcdefg", 4); got != "abc- defg" {
+		t.Errorf("HyphenateLongWords = %q", got)
+	}
+	wrapped := purse.JoinWrapped(strings.Fields(purse.HyphenateLongWords("a 0123456789abcdef", 6)), " ", 6)
+	for _, line := range purse.MakeLines(wrapped) {
+		if purse.VisualWidth(line) > 6 {
+			t.Errorf("wrapped line %q is wider than 6 columns", line)
+		}
+	}
+}
+
+func TestGroupSimilar(t *testing.T) {
+	lines := []string{
+		"connection reset by peer 10.0.0.1",
+		"disk usage at 91%",
+		"connection reset by peer 10.0.0.2",
+		"disk usage at 93%",
+		"shutting down",
+	}
+	groups := purse.GroupSimilar(lines, 0.8)
+	if got := fmt.Sprint(groups); got != "[[connection reset by peer 10.0.0.1 connection reset by peer 10.0.0.2] [disk usage at 91% disk usage at 93%] [shutting down]]" {
+		t.Errorf("GroupSimilar = %s", got)
+	}
+	if got := purse.GroupSimilar(lines, 1.01); len(got) != len(lines) {
+		t.Errorf("GroupSimilar above 1 made %d groups", len(got))
+	}
+}
+
+func TestCollapseRepeats(t *testing.T) {
+	got := purse.CollapseRepeats([]string{"a", "a", "a", "b", "a", "a"})
+	if fmt.Sprint(got) != "[{a 3} {b 1} {a 2}]" {
+		t.Errorf("CollapseRepeats = %v", got)
+	}
+	if got := purse.CollapseRepeats(nil); len(got) != 0 {
+		t.Errorf("CollapseRepeats(nil) = %v", got)
+	}
+}
+
+func TestCache(t *testing.T) {
+	c := purse.NewCache(2)
+	c.Put("a", "1")
+	c.Put("b", "2")
+	c.Get("a")
+	c.Put("c", "3")
+	if _, ok := c.Get("b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != "1" {
+		t.Errorf("Get(a) = %q, %v", v, ok)
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d", c.Len())
+	}
+	c.Purge()
+	if _, ok := c.Get("a"); ok || c.Len() != 0 {
+		t.Error("Purge left entries behind")
+	}
+
+	var calls int64
+	var mu sync.Mutex
+	dedent := purse.Cached(func(s string) string {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return purse.Dedent(s)
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if got := dedent(fmt.Sprintf("\t\tx%d", i%5)); got != fmt.Sprintf("x%d", i%5) {
+				t.Errorf("cached Dedent = %q", got)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if calls < 5 || calls > 50 {
+		t.Errorf("fn called %d times", calls)
+	}
+	before := calls
+	dedent("\t\tx1")
+	if calls != before {
+		t.Error("cached result was recomputed")
+	}
+}
+
+func TestApplyOps(t *testing.T) {
+	recipe := `[
+		{"op": "StripComments", "style": "shell"},
+		{"op": "TrimTrailingSpaces"},
+		{"op": "RemoveEmptyLines"},
+		{"op": "Dedent"},
+		{"op": "ReplaceAll", "old": "=", "new": ": "},
+		{"op": "PrefixLines", "prefix": "> "}
+	]`
+	var ops []purse.Op
+	if err := json.Unmarshal([]byte(recipe), &ops); err != nil {
+		t.Fatal(err)
+	}
+	got, err := purse.Apply("  # settings\n  a=1\n\n  b=2 # two\n", ops)
+	if err != nil || got != "> a: 1\n> b: 2" {
+		t.Errorf("Apply = %q, %v", got, err)
+	}
+	data, err := json.Marshal(purse.Op{Name: "Indent", Level: 1, Unit: "\t"})
+	if err != nil || string(data) != `{"op":"Indent","unit":"\t","level":1}` {
+		t.Errorf("json.Marshal(Op) = %s, %v", data, err)
+	}
+	if _, err := purse.Apply("x", []purse.Op{{Name: "Flatten"}, {Name: "Nope"}}); err == nil || !strings.Contains(err.Error(), `op 1: unknown operation "Nope"`) {
+		t.Errorf("Apply with an unknown op: %v", err)
+	}
+	if _, err := purse.Apply("x", []purse.Op{{Name: "ReplaceAll"}}); err == nil {
+		t.Error("Apply accepted ReplaceAll without old")
+	}
+	var missing []purse.Op
+	json.Unmarshal([]byte(`[{"op": "NormalizeLineEndings"}]`), &missing)
+	if got, err := purse.Apply("a\r\nb\rc", missing); err != nil || got != "a\nb\nc" {
+		t.Errorf("NormalizeLineEndings without new = %q, %v", got, err)
+	}
+	if _, err := purse.Apply("a\nb", []purse.Op{{Name: "NormalizeLineEndings", New: " "}}); err == nil {
+		t.Error("Apply accepted NormalizeLineEndings to a non line ending")
+	}
+	json.Unmarshal([]byte(`[{"op": "Truncate", "ellipsis": "…"}]`), &missing)
+	if got, err := purse.Apply("keep me", missing); err == nil || !strings.Contains(err.Error(), "width") {
+		t.Errorf("Truncate without width = %q, %v", got, err)
+	}
+	if _, err := purse.Apply("x", []purse.Op{{Name: "Indent", Unit: "\t"}}); err == nil {
+		t.Error("Apply accepted Indent without level")
+	}
+	purse.RegisterOp("Shout", func(s string, _ purse.Op) (string, error) {
+		return strings.ToUpper(s) + "!", nil
+	})
+	if got, err := purse.Apply("hi", []purse.Op{{Name: "Shout"}}); err != nil || got != "HI!" {
+		t.Errorf("Apply with a registered op = %q, %v", got, err)
+	}
+	if names := purse.OpNames(); !purse.SliceContains(names, "Shout") || !purse.SliceContains(names, "PrefixLines") {
+		t.Errorf("OpNames() = %v", names)
+	}
+}
+
+func TestSourceMaps(t *testing.T) {
+	input := "\n\tfirst\n\n\t\tsecond {{name}}\n\n\tthird"
+	type mapped struct {
+		name string
+		want string
+		got  string
+		sm   *purse.SourceMap
+	}
+	var checks []mapped
+	got, sm := purse.RemoveEmptyLinesMapped(input)
+	checks = append(checks, mapped{"RemoveEmptyLines", purse.RemoveEmptyLines(input), got, sm})
+	got, sm = purse.FlattenMapped(input)
+	checks = append(checks, mapped{"Flatten", purse.Flatten(input), got, sm})
+	got, sm = purse.DedentMapped(input)
+	checks = append(checks, mapped{"Dedent", purse.Dedent(input), got, sm})
+	upper := func(inner string) string { return strings.ToUpper(inner) + "!" }
+	got, sm = purse.ReplaceBetweenMapped(input, "{{", "}}", upper)
+	checks = append(checks, mapped{"ReplaceBetween", purse.ReplaceBetween(input, "{{", "}}", upper), got, sm})
+
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%sMapped output = %q, want %q", c.name, c.got, c.want)
+			continue
+		}
+		// Every copied character must map back to the same character in the input
+		for _, token := range []string{"first", "second", "third"} {
+			i := strings.Index(c.got, token)
+			if in := c.sm.Original(i); !strings.HasPrefix(input[in:], token) {
+				t.Errorf("%s: %q at output %d maps to input %d (%q)", c.name, token, i, in, input[in:])
+			}
+		}
+	}
+
+	out, sm := purse.RemoveEmptyLinesMapped(input)
+	line, col := purse.LineCol(out, strings.Index(out, "second"))
+	if l, c := sm.OriginalLineCol(line, col); l != 4 || c != 3 {
+		t.Errorf("OriginalLineCol(%d, %d) = %d, %d, want 4, 3", line, col, l, c)
+	}
+	out, sm = purse.ReplaceBetweenMapped(input, "{{", "}}", upper)
+	if in := sm.Original(strings.Index(out, "AME!")); in != strings.Index(input, "name") {
+		t.Errorf("generated text maps to %d", in)
+	}
+	if in := sm.Original(len(out) + 10); in != len(input) {
+		t.Errorf("Original past the end = %d", in)
+	}
+}
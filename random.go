@@ -0,0 +1,114 @@
+package purse
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RandSource is a source of random text that can be seeded for reproducible output.
+// It is safe for concurrent use. The package-level random helpers share a default
+// source, which SetRandSource replaces.
+type RandSource struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewRandSource creates a RandSource seeded with seed, so every RandSource created with
+// the same seed produces the same sequence.
+func NewRandSource(seed int64) *RandSource {
+	return &RandSource{rng: rand.New(rand.NewSource(seed))}
+}
+
+var defaultRand = &RandSource{rng: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// SetRandSource replaces the source behind the package's random helpers,
+// so tests can seed it for reproducible output.
+func SetRandSource(src rand.Source) {
+	defaultRand.mu.Lock()
+	defer defaultRand.mu.Unlock()
+	defaultRand.rng = rand.New(src)
+}
+
+func (rs *RandSource) intn(n int) int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.rng.Intn(n)
+}
+
+func (rs *RandSource) float64() float64 {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	return rs.rng.Float64()
+}
+
+func randIntn(n int) int {
+	return defaultRand.intn(n)
+}
+
+func randFloat64() float64 {
+	return defaultRand.float64()
+}
+
+// WeightedString is an option for PickWeighted.
+type WeightedString struct {
+	Value  string
+	Weight float64
+}
+
+// PickWeighted picks one option at random, with odds proportional to its weight.
+func PickWeighted(options []WeightedString) (string, error) {
+	total := 0.0
+	for _, option := range options {
+		if option.Weight < 0 {
+			return "", fmt.Errorf("option %q has negative weight %v", option.Value, option.Weight)
+		}
+		total += option.Weight
+	}
+	if total == 0 {
+		return "", fmt.Errorf("no options with a positive weight")
+	}
+	target := randFloat64() * total
+	for _, option := range options {
+		target -= option.Weight
+		if target < 0 {
+			return option.Value, nil
+		}
+	}
+	// Floating point rounding can leave target at zero, so fall back to the last weighted option
+	for i := len(options) - 1; i >= 0; i-- {
+		if options[i].Weight > 0 {
+			return options[i].Value, nil
+		}
+	}
+	return "", nil
+}
+
+// RandStrWeightedCharset generates a random string of specified length, drawing each
+// rune with odds proportional to its weight. Runes with no positive weight never appear.
+func RandStrWeightedCharset(length int, weights map[rune]float64) string {
+	runes := make([]rune, 0, len(weights))
+	for r, w := range weights {
+		if w > 0 {
+			runes = append(runes, r)
+		}
+	}
+	if len(runes) == 0 || length <= 0 {
+		return ""
+	}
+	// Map order is random, so sort to keep output reproducible under a seeded source
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+	options := make([]WeightedString, len(runes))
+	for i, r := range runes {
+		options[i] = WeightedString{Value: string(r), Weight: weights[r]}
+	}
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		pick, _ := PickWeighted(options)
+		sb.WriteString(pick)
+	}
+	return sb.String()
+}
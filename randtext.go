@@ -0,0 +1,120 @@
+package purse
+
+import (
+	"strings"
+)
+
+const (
+	hexDigits        = "0123456789abcdef"
+	base62Digits     = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	loremOpening     = "lorem ipsum dolor sit amet"
+	minSentenceWords = 6
+	maxSentenceWords = 12
+)
+
+// Word returns a random lorem ipsum word.
+func (rs *RandSource) Word() string {
+	return fakeWords[rs.intn(len(fakeWords))]
+}
+
+// Sentence returns a capitalized sentence of the given number of random words ending
+// in a period.
+func (rs *RandSource) Sentence(words int) string {
+	if words <= 0 {
+		return ""
+	}
+	picked := make([]string, words)
+	for i := range picked {
+		picked[i] = rs.Word()
+	}
+	return sentenceOf(picked)
+}
+
+// Paragraph returns the given number of sentences of random length, separated by spaces.
+func (rs *RandSource) Paragraph(sentences int) string {
+	out := make([]string, 0, max(sentences, 0))
+	for i := 0; i < sentences; i++ {
+		out = append(out, rs.Sentence(rs.sentenceLength()))
+	}
+	return strings.Join(out, " ")
+}
+
+// Hex returns n random lowercase hex digits.
+func (rs *RandSource) Hex(n int) string {
+	return rs.fromCharset(n, hexDigits)
+}
+
+// Base62 returns n random characters drawn from digits and upper- and lower-case letters.
+func (rs *RandSource) Base62(n int) string {
+	return rs.fromCharset(n, base62Digits)
+}
+
+// LoremIpsum returns placeholder text of exactly words words. It opens with the familiar
+// "Lorem ipsum dolor sit amet" and continues with random words, broken into sentences.
+func (rs *RandSource) LoremIpsum(words int) string {
+	if words <= 0 {
+		return ""
+	}
+	picked := strings.Fields(loremOpening)
+	if words < len(picked) {
+		picked = picked[:words]
+	}
+	for len(picked) < words {
+		picked = append(picked, rs.Word())
+	}
+	var sentences []string
+	for len(picked) > 0 {
+		n := min(rs.sentenceLength(), len(picked))
+		sentences = append(sentences, sentenceOf(picked[:n]))
+		picked = picked[n:]
+	}
+	return strings.Join(sentences, " ")
+}
+
+// sentenceLength picks a natural-looking number of words for a sentence.
+func (rs *RandSource) sentenceLength() int {
+	return minSentenceWords + rs.intn(maxSentenceWords-minSentenceWords+1)
+}
+
+// fromCharset returns n bytes drawn at random from charset.
+func (rs *RandSource) fromCharset(n int, charset string) string {
+	if n <= 0 {
+		return ""
+	}
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = charset[rs.intn(len(charset))]
+	}
+	return string(b)
+}
+
+// sentenceOf joins words into a sentence with a capital letter and a final period.
+func sentenceOf(words []string) string {
+	s := strings.Join(words, " ")
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+// RandWord returns a random lorem ipsum word from the default source.
+func RandWord() string {
+	return defaultRand.Word()
+}
+
+// RandParagraph returns the given number of random sentences from the default source.
+func RandParagraph(sentences int) string {
+	return defaultRand.Paragraph(sentences)
+}
+
+// RandHex returns n random lowercase hex digits from the default source.
+func RandHex(n int) string {
+	return defaultRand.Hex(n)
+}
+
+// RandBase62 returns n random base62 characters from the default source.
+func RandBase62(n int) string {
+	return defaultRand.Base62(n)
+}
+
+// LoremIpsum returns words words of placeholder text from the default source.
+func LoremIpsum(words int) string {
+	return defaultRand.LoremIpsum(words)
+}
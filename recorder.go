@@ -0,0 +1,83 @@
+package purse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Step describes one transformation applied through a Recorder. Line counts use
+// CountLines, and Added and Removed count lines that appear only after or only before
+// the step, regardless of order.
+type Step struct {
+	Name        string
+	LinesBefore int
+	LinesAfter  int
+	Added       int
+	Removed     int
+	Changed     bool
+}
+
+// String formats the step as a single changelog line, such as
+// "RemoveEmptyLines: 10 -> 7 lines (+0 -3)".
+func (st Step) String() string {
+	if !st.Changed {
+		return st.Name + ": unchanged"
+	}
+	return fmt.Sprintf("%s: %d -> %d %s (+%d -%d)", st.Name, st.LinesBefore, st.LinesAfter,
+		Pluralize("line", st.LinesAfter), st.Added, st.Removed)
+}
+
+// Recorder applies named transformations and remembers them, so the same pipeline can
+// be replayed on other input and its effect explained afterwards.
+type Recorder struct {
+	fns   []func(string) string
+	steps []Step
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Apply runs fn on s, records it under name together with what it changed, and returns
+// the result.
+func (r *Recorder) Apply(name string, s string, fn func(string) string) string {
+	out := fn(s)
+	step := Step{
+		Name:        name,
+		LinesBefore: CountLines(s),
+		LinesAfter:  CountLines(out),
+		Changed:     out != s,
+	}
+	if step.Changed {
+		diff := DiffSlices(MakeLines(s), MakeLines(out), IgnoreOrder())
+		step.Added, step.Removed = len(diff.Added), len(diff.Removed)
+	}
+	r.fns = append(r.fns, fn)
+	r.steps = append(r.steps, step)
+	return out
+}
+
+// Replay runs every recorded transformation on s in the order it was applied.
+// Replaying does not record new steps.
+func (r *Recorder) Replay(s string) string {
+	for _, fn := range r.fns {
+		s = fn(s)
+	}
+	return s
+}
+
+// Steps returns what each recorded transformation did, in order.
+func (r *Recorder) Steps() []Step {
+	return append([]Step(nil), r.steps...)
+}
+
+// Changelog returns one numbered line per recorded step, suitable for showing users
+// what a pipeline rewrote.
+func (r *Recorder) Changelog() string {
+	lines := make([]string, len(r.steps))
+	for i, step := range r.steps {
+		lines[i] = fmt.Sprintf("%d. %s", i+1, step)
+	}
+	return strings.Join(lines, "\n")
+}
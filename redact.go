@@ -0,0 +1,39 @@
+package purse
+
+// Mask replaces every rune of s with maskChar except the first keepStart and the last
+// keepEnd runes, as in Mask("sk-12345678", 3, 2, '*') == "sk-******78". If the kept
+// runes would cover the whole string, all of it is masked so short secrets never leak.
+func Mask(s string, keepStart, keepEnd int, maskChar rune) string {
+	runes := []rune(s)
+	keepStart, keepEnd = max(keepStart, 0), max(keepEnd, 0)
+	if keepStart+keepEnd >= len(runes) {
+		keepStart, keepEnd = 0, 0
+	}
+	for i := keepStart; i < len(runes)-keepEnd; i++ {
+		runes[i] = maskChar
+	}
+	return string(runes)
+}
+
+// RedactBetween replaces the text inside every start/end pair with replacement, keeping
+// the delimiters so the redacted output still shows where a value was.
+func RedactBetween(s, start, end, replacement string) string {
+	return ReplaceBetween(s, start, end, func(string) string {
+		return replacement
+	})
+}
+
+// RedactPattern replaces every occurrence of each secret with replacement in a single
+// pass. Empty secrets are ignored and longer secrets win over shorter ones they contain.
+func RedactPattern(s string, secrets []string, replacement string) string {
+	pairs := make(map[string]string, len(secrets))
+	for _, secret := range secrets {
+		if secret != "" {
+			pairs[secret] = replacement
+		}
+	}
+	if len(pairs) == 0 {
+		return s
+	}
+	return ReplaceAllPairs(s, pairs)
+}
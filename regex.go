@@ -0,0 +1,41 @@
+package purse
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// neverMatch is a pattern that matches nothing, used for an empty list of literals.
+const neverMatch = `[^\x00-\x{10FFFF}]`
+
+// QuoteMetaAll escapes the regular expression metacharacters in every item.
+func QuoteMetaAll(items []string) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = regexp.QuoteMeta(item)
+	}
+	return out
+}
+
+// RegexFromLiterals builds a pattern that matches any of the literals. Duplicates and
+// empty literals are dropped, and longer literals are tried first so that "foobar" wins
+// over "foo". An empty list produces a pattern that never matches.
+func RegexFromLiterals(literals []string) string {
+	var sorted []string
+	for _, literal := range RemoveDuplicatesInSlice(literals) {
+		if literal != "" {
+			sorted = append(sorted, literal)
+		}
+	}
+	if len(sorted) == 0 {
+		return neverMatch
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) > len(sorted[j])
+		}
+		return sorted[i] < sorted[j]
+	})
+	return "(?:" + strings.Join(QuoteMetaAll(sorted), "|") + ")"
+}
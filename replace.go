@@ -0,0 +1,157 @@
+package purse
+
+import (
+	"sort"
+	"strings"
+)
+
+// Replacer performs several replacements in a single pass, so replacement
+// output is never matched again.
+type Replacer struct {
+	r *strings.Replacer
+}
+
+// NewReplacer creates a Replacer from old, new pairs. When several olds match
+// at the same position, the one listed first wins. It panics if given an odd
+// number of arguments.
+func NewReplacer(pairs ...string) *Replacer {
+	return &Replacer{r: strings.NewReplacer(pairs...)}
+}
+
+// Replace returns a copy of s with all replacements performed.
+func (r *Replacer) Replace(s string) string {
+	return r.r.Replace(s)
+}
+
+// ReplaceAllPairs replaces every key of pairs with its value in a single pass.
+// Longer keys take priority over shorter keys that match at the same position.
+func ReplaceAllPairs(s string, pairs map[string]string) string {
+	keys := make([]string, 0, len(pairs))
+	for k := range pairs {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	args := make([]string, 0, len(keys)*2)
+	for _, k := range keys {
+		args = append(args, k, pairs[k])
+	}
+	return NewReplacer(args...).Replace(s)
+}
+
+// expandCharSet expands tr-style ranges such as "a-z" into the runes they cover.
+// A '-' at the start or end of the set is taken literally.
+func expandCharSet(set string) []rune {
+	runes := []rune(set)
+	var out []rune
+	for i := 0; i < len(runes); i++ {
+		if i+2 < len(runes) && runes[i+1] == '-' && runes[i] <= runes[i+2] {
+			for r := runes[i]; r <= runes[i+2]; r++ {
+				out = append(out, r)
+			}
+			i += 2
+			continue
+		}
+		out = append(out, runes[i])
+	}
+	return out
+}
+
+// Translate maps each rune of from to the rune at the same position in to, like tr(1).
+// Both sets accept ranges such as "a-z". If to is shorter than from, its last rune is
+// repeated; if to is empty, s is returned unchanged.
+func Translate(s, from, to string) string {
+	src, dst := expandCharSet(from), expandCharSet(to)
+	if len(dst) == 0 {
+		return s
+	}
+	mapping := make(map[rune]rune, len(src))
+	for i, r := range src {
+		if _, seen := mapping[r]; seen {
+			continue
+		}
+		mapping[r] = dst[min(i, len(dst)-1)]
+	}
+	return strings.Map(func(r rune) rune {
+		if m, ok := mapping[r]; ok {
+			return m
+		}
+		return r
+	}, s)
+}
+
+// Delete removes every rune in set from s, like tr -d. The set accepts ranges such as "0-9".
+func Delete(s, set string) string {
+	del := make(map[rune]bool)
+	for _, r := range expandCharSet(set) {
+		del[r] = true
+	}
+	return strings.Map(func(r rune) rune {
+		if del[r] {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// TransformOutside applies fn to the text outside protected regions, which run from
+// protectStart to the next protectEnd, delimiters included. Protected regions are copied
+// through untouched, and an unclosed region is protected to the end of the string.
+func TransformOutside(s string, protectStart, protectEnd string, fn func(string) string) string {
+	if protectStart == "" || protectEnd == "" {
+		return fn(s)
+	}
+	var sb strings.Builder
+	for {
+		i := strings.Index(s, protectStart)
+		if i == -1 {
+			sb.WriteString(fn(s))
+			return sb.String()
+		}
+		sb.WriteString(fn(s[:i]))
+		j := strings.Index(s[i+len(protectStart):], protectEnd)
+		if j == -1 {
+			sb.WriteString(s[i:])
+			return sb.String()
+		}
+		end := i + len(protectStart) + j + len(protectEnd)
+		sb.WriteString(s[i:end])
+		s = s[end:]
+	}
+}
+
+// ReplaceFunc replaces each non-overlapping occurrence of target with the result of fn,
+// which receives the match and its 0-based occurrence number.
+func ReplaceFunc(s, target string, fn func(match string, index int) string) string {
+	var sb strings.Builder
+	pos := 0
+	for n, i := range AllIndexes(s, target) {
+		sb.WriteString(s[pos:i])
+		sb.WriteString(fn(target, n))
+		pos = i + len(target)
+	}
+	sb.WriteString(s[pos:])
+	return sb.String()
+}
+
+// ReplaceBetween rewrites the text inside every start/end pair with the result of fn,
+// keeping the delimiters in place.
+func ReplaceBetween(s, start, end string, fn func(inner string) string) string {
+	var sb strings.Builder
+	pos := 0
+	for _, span := range FindAll(s, start, end) {
+		sb.WriteString(s[pos:span.Start])
+		sb.WriteString(start)
+		sb.WriteString(fn(span.Text[len(start) : len(span.Text)-len(end)]))
+		sb.WriteString(end)
+		pos = span.End
+	}
+	sb.WriteString(s[pos:])
+	return sb.String()
+}
@@ -0,0 +1,48 @@
+package purse
+
+// RuleOption configures Rule.
+type RuleOption func(*ruleConfig)
+
+type ruleConfig struct {
+	title string
+}
+
+// WithTitle centers a title within a rule.
+func WithTitle(title string) RuleOption {
+	return func(c *ruleConfig) {
+		c.title = title
+	}
+}
+
+// Rule builds a divider line width columns wide out of char.
+func Rule(width int, char rune, opts ...RuleOption) string {
+	if width <= 0 {
+		return ""
+	}
+	var cfg ruleConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	fill := string(char)
+	if cfg.title == "" {
+		return RepeatToWidth(fill, width)
+	}
+	label := " " + cfg.title + " "
+	gap := width - VisualWidth(label)
+	if gap < 2 {
+		return Truncate(cfg.title, width, "…")
+	}
+	left := gap / 2
+	return RepeatToWidth(fill, left) + label + RepeatToWidth(fill, gap-left)
+}
+
+// Underline returns s followed by a line of char as wide as the widest line of s.
+func Underline(s string, char rune) string {
+	width := 0
+	for _, line := range MakeLines(s) {
+		if w := VisualWidth(line); w > width {
+			width = w
+		}
+	}
+	return s + "\n" + RepeatToWidth(string(char), width)
+}
@@ -0,0 +1,140 @@
+package purse
+
+import (
+	"strings"
+)
+
+// CountOccurrences counts the non-overlapping occurrences of sub in s.
+func CountOccurrences(s, sub string) int {
+	if sub == "" {
+		return 0
+	}
+	return strings.Count(s, sub)
+}
+
+// AllIndexes returns the byte offsets of every non-overlapping occurrence of sub in s.
+// Each search resumes just past the previous match, so the whole scan is a single pass.
+func AllIndexes(s, sub string) []int {
+	if sub == "" {
+		return nil
+	}
+	var out []int
+	pos := 0
+	for {
+		i := strings.Index(s[pos:], sub)
+		if i == -1 {
+			return out
+		}
+		out = append(out, pos+i)
+		pos += i + len(sub)
+	}
+}
+
+// CountLines returns the number of lines MakeLines would produce, or 0 for an empty string.
+func CountLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(s, "\n") + 1
+}
+
+// Span is a located piece of text. Start and End are byte offsets into the searched
+// string and Line is the 1-based line on which the span starts.
+type Span struct {
+	Start int
+	End   int
+	Text  string
+	Line  int
+}
+
+// FindAll returns every non-overlapping substring that runs from start to the next end
+// after it, delimiters included, in order of appearance.
+func FindAll(input, start, end string) []Span {
+	var spans []Span
+	if start == "" || end == "" {
+		return spans
+	}
+	pos, line, counted := 0, 1, 0
+	for {
+		i := strings.Index(input[pos:], start)
+		if i == -1 {
+			return spans
+		}
+		i += pos
+		j := strings.Index(input[i+len(start):], end)
+		if j == -1 {
+			return spans
+		}
+		stop := i + len(start) + j + len(end)
+		line += strings.Count(input[counted:i], "\n")
+		counted = i
+		spans = append(spans, Span{Start: i, End: stop, Text: input[i:stop], Line: line})
+		pos = stop
+	}
+}
+
+// FindAllLineNumbers returns the distinct 1-based line numbers on which FindAll matches start.
+func FindAllLineNumbers(input, start, end string) []int {
+	var lines []int
+	for _, span := range FindAll(input, start, end) {
+		if n := len(lines); n == 0 || lines[n-1] != span.Line {
+			lines = append(lines, span.Line)
+		}
+	}
+	return lines
+}
+
+// DupLineGroup is a line that appears more than once, with the 1-based numbers of every
+// line it appears on. Text is the first occurrence as written.
+type DupLineGroup struct {
+	Text  string
+	Lines []int
+}
+
+// DupLineOption configures FindDuplicateLines.
+type DupLineOption func(*dupLineConfig)
+
+type dupLineConfig struct {
+	ignoreWhitespace bool
+}
+
+// IgnoreWhitespace makes FindDuplicateLines treat lines as equal when they differ only
+// in leading, trailing or repeated whitespace.
+func IgnoreWhitespace() DupLineOption {
+	return func(c *dupLineConfig) {
+		c.ignoreWhitespace = true
+	}
+}
+
+// FindDuplicateLines reports every line of s that appears more than once, in order of
+// first appearance. Blank lines are never reported.
+func FindDuplicateLines(s string, opts ...DupLineOption) []DupLineGroup {
+	var cfg dupLineConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	var groups []DupLineGroup
+	seen := make(map[string]int)
+	for i, line := range MakeLines(s) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key := line
+		if cfg.ignoreWhitespace {
+			key = strings.Join(strings.Fields(line), " ")
+		}
+		if g, ok := seen[key]; ok {
+			groups[g].Lines = append(groups[g].Lines, i+1)
+			continue
+		}
+		seen[key] = len(groups)
+		groups = append(groups, DupLineGroup{Text: line, Lines: []int{i + 1}})
+	}
+	dups := groups[:0]
+	for _, g := range groups {
+		if len(g.Lines) > 1 {
+			dups = append(dups, g)
+		}
+	}
+	return dups
+}
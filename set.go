@@ -0,0 +1,97 @@
+package purse
+
+// Set is a collection of unique strings that remembers insertion order.
+// The zero value is an empty set ready to use.
+type Set struct {
+	order []string
+	index map[string]int // position of each member in order
+}
+
+// NewSet creates a Set holding the given items.
+func NewSet(items ...string) *Set {
+	s := &Set{index: make(map[string]int)}
+	for _, item := range items {
+		s.Add(item)
+	}
+	return s
+}
+
+// Add inserts items that are not yet in the set.
+func (s *Set) Add(items ...string) {
+	if s.index == nil {
+		s.index = make(map[string]int)
+	}
+	for _, item := range items {
+		if _, ok := s.index[item]; ok {
+			continue
+		}
+		s.index[item] = len(s.order)
+		s.order = append(s.order, item)
+	}
+}
+
+// Has reports whether item is in the set.
+func (s *Set) Has(item string) bool {
+	_, ok := s.index[item]
+	return ok
+}
+
+// Remove deletes item from the set.
+func (s *Set) Remove(item string) {
+	if _, ok := s.index[item]; !ok {
+		return
+	}
+	delete(s.index, item)
+	// Removed members stay in order until they make up half of it, keeping Remove cheap
+	if len(s.order) > 2*len(s.index) {
+		s.order = s.ToSlice()
+		for i, member := range s.order {
+			s.index[member] = i
+		}
+	}
+}
+
+// Len returns the number of items in the set.
+func (s *Set) Len() int {
+	return len(s.index)
+}
+
+// ToSlice returns the members in insertion order.
+func (s *Set) ToSlice() []string {
+	out := make([]string, 0, len(s.index))
+	for i, item := range s.order {
+		if pos, ok := s.index[item]; ok && pos == i {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Union returns a new set with the members of s followed by the new members of other.
+func (s *Set) Union(other *Set) *Set {
+	out := NewSet(s.ToSlice()...)
+	out.Add(other.ToSlice()...)
+	return out
+}
+
+// Intersect returns a new set with the members of s that are also in other.
+func (s *Set) Intersect(other *Set) *Set {
+	out := NewSet()
+	for _, item := range s.ToSlice() {
+		if other.Has(item) {
+			out.Add(item)
+		}
+	}
+	return out
+}
+
+// Diff returns a new set with the members of s that are not in other.
+func (s *Set) Diff(other *Set) *Set {
+	out := NewSet()
+	for _, item := range s.ToSlice() {
+		if !other.Has(item) {
+			out.Add(item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,259 @@
+package purse
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// CommonPrefix returns the longest prefix shared by every item, never splitting a rune.
+func CommonPrefix(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	prefix := items[0]
+	for _, item := range items[1:] {
+		n := 0
+		for n < len(prefix) && n < len(item) && prefix[n] == item[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	// Back off only if the cut lands inside a rune in some item; invalid bytes
+	// within the shared prefix are kept as they are
+	n := len(prefix)
+	for _, item := range items {
+		for n > 0 && n < len(item) && !utf8.RuneStart(item[n]) {
+			n--
+		}
+	}
+	return prefix[:n]
+}
+
+// CommonSuffix returns the longest suffix shared by every item, never splitting a rune.
+func CommonSuffix(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	suffix := items[0]
+	for _, item := range items[1:] {
+		n := 0
+		for n < len(suffix) && n < len(item) && suffix[len(suffix)-1-n] == item[len(item)-1-n] {
+			n++
+		}
+		suffix = suffix[len(suffix)-n:]
+	}
+	for len(suffix) > 0 && !utf8.RuneStart(suffix[0]) {
+		suffix = suffix[1:]
+	}
+	return suffix
+}
+
+// TrimCommonPrefix removes the prefix shared by every item.
+func TrimCommonPrefix(items []string) []string {
+	n := len(CommonPrefix(items))
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item[n:]
+	}
+	return out
+}
+
+// Windows returns every run of size consecutive elements, sliding one element at a time.
+// The windows share the slice's backing array but are capped so appending to one is safe.
+func Windows[T any](slice []T, size int) [][]T {
+	if size <= 0 || size > len(slice) {
+		return nil
+	}
+	out := make([][]T, 0, len(slice)-size+1)
+	for i := 0; i+size <= len(slice); i++ {
+		out = append(out, slice[i:i+size:i+size])
+	}
+	return out
+}
+
+// NGrams returns every run of n consecutive runes in s.
+func NGrams(s string, n int) []string {
+	if n <= 0 {
+		return nil
+	}
+	// Record where each rune starts so windows can be cut from s without copying
+	starts := make([]int, 0, len(s)+1)
+	for i := range s {
+		starts = append(starts, i)
+	}
+	starts = append(starts, len(s))
+	var out []string
+	for i := 0; i+n < len(starts); i++ {
+		out = append(out, s[starts[i]:starts[i+n]])
+	}
+	return out
+}
+
+// SearchSorted finds where item is, or would be inserted, in an ascending slice.
+func SearchSorted(items []string, item string) (int, bool) {
+	return slices.BinarySearch(items, item)
+}
+
+// SearchSortedFunc is SearchSorted for slices ordered by cmp, which returns a negative
+// number, zero or a positive number as a sorts before, equal to or after b.
+func SearchSortedFunc(items []string, item string, cmp func(a, b string) int) (int, bool) {
+	return slices.BinarySearchFunc(items, item, cmp)
+}
+
+// InsertSorted inserts item into an ascending slice, keeping it sorted.
+func InsertSorted(items []string, item string) []string {
+	i, _ := SearchSorted(items, item)
+	return slices.Insert(items, i, item)
+}
+
+// InsertSortedFunc inserts item into a slice ordered by cmp, keeping it sorted.
+func InsertSortedFunc(items []string, item string, cmp func(a, b string) int) []string {
+	i, _ := SearchSortedFunc(items, item, cmp)
+	return slices.Insert(items, i, item)
+}
+
+// MatchOption configures the slice filters.
+type MatchOption func(*matchConfig)
+
+type matchConfig struct {
+	ignoreCase bool
+}
+
+// IgnoreCase makes a filter compare strings under Unicode case folding.
+func IgnoreCase() MatchOption {
+	return func(c *matchConfig) {
+		c.ignoreCase = true
+	}
+}
+
+func filterItems(items []string, opts []MatchOption, exact, folded func(item string) bool) []string {
+	var cfg matchConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	match := exact
+	if cfg.ignoreCase {
+		match = folded
+	}
+	var out []string
+	for _, item := range items {
+		if match(item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// FilterPrefix returns the items that start with prefix.
+func FilterPrefix(items []string, prefix string, opts ...MatchOption) []string {
+	n := utf8.RuneCountInString(prefix)
+	return filterItems(items, opts,
+		func(item string) bool { return strings.HasPrefix(item, prefix) },
+		func(item string) bool {
+			runes := []rune(item)
+			return len(runes) >= n && strings.EqualFold(string(runes[:n]), prefix)
+		})
+}
+
+// FilterSuffix returns the items that end with suffix.
+func FilterSuffix(items []string, suffix string, opts ...MatchOption) []string {
+	n := utf8.RuneCountInString(suffix)
+	return filterItems(items, opts,
+		func(item string) bool { return strings.HasSuffix(item, suffix) },
+		func(item string) bool {
+			runes := []rune(item)
+			return len(runes) >= n && strings.EqualFold(string(runes[len(runes)-n:]), suffix)
+		})
+}
+
+// FilterContains returns the items that contain sub.
+func FilterContains(items []string, sub string, opts ...MatchOption) []string {
+	return filterItems(items, opts,
+		func(item string) bool { return strings.Contains(item, sub) },
+		func(item string) bool { return ContainsFold(item, sub) })
+}
+
+// TrimEach trims surrounding whitespace from every item.
+func TrimEach(items []string) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = strings.TrimSpace(item)
+	}
+	return out
+}
+
+// CompactBlank drops items that are empty or only whitespace.
+func CompactBlank(items []string) []string {
+	var out []string
+	for _, item := range items {
+		if strings.TrimSpace(item) != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// CleanOption configures CleanSlice.
+type CleanOption func(*cleanConfig)
+
+type cleanConfig struct {
+	keepWhitespace bool
+	keepBlank      bool
+	keepDuplicates bool
+}
+
+// KeepWhitespace stops CleanSlice from trimming items.
+func KeepWhitespace() CleanOption {
+	return func(c *cleanConfig) { c.keepWhitespace = true }
+}
+
+// KeepBlank stops CleanSlice from dropping blank items.
+func KeepBlank() CleanOption {
+	return func(c *cleanConfig) { c.keepBlank = true }
+}
+
+// KeepDuplicates stops CleanSlice from removing duplicate items.
+func KeepDuplicates() CleanOption {
+	return func(c *cleanConfig) { c.keepDuplicates = true }
+}
+
+// CleanSlice trims every item, drops blank items and removes duplicates, the usual
+// cleanup after splitting user input. Options turn individual steps off.
+func CleanSlice(items []string, opts ...CleanOption) []string {
+	var cfg cleanConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	out := items
+	if !cfg.keepWhitespace {
+		out = TrimEach(out)
+	}
+	if !cfg.keepBlank {
+		out = CompactBlank(out)
+	}
+	if !cfg.keepDuplicates {
+		out = RemoveDuplicatesInSlice(out)
+	}
+	return out
+}
+
+// RepeatedLine is a line together with how many times in a row it occurred.
+type RepeatedLine struct {
+	Line  string
+	Count int
+}
+
+// CollapseRepeats merges runs of identical consecutive lines into one entry each, the
+// way a logger reports "last message repeated N times".
+func CollapseRepeats(lines []string) []RepeatedLine {
+	var out []RepeatedLine
+	for _, line := range lines {
+		if n := len(out); n > 0 && out[n-1].Line == line {
+			out[n-1].Count++
+			continue
+		}
+		out = append(out, RepeatedLine{Line: line, Count: 1})
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package purse
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SnippetID identifies a snippet by the hash of its content.
+type SnippetID string
+
+// SnippetStore holds each distinct snippet once, keyed by its content hash.
+// It is safe for concurrent use.
+type SnippetStore struct {
+	mu       sync.RWMutex
+	snippets map[SnippetID]string
+}
+
+// NewSnippetStore creates an empty SnippetStore.
+func NewSnippetStore() *SnippetStore {
+	return &SnippetStore{snippets: make(map[SnippetID]string)}
+}
+
+// SnippetIDOf returns the ID a snippet is stored under.
+func SnippetIDOf(s string) SnippetID {
+	return SnippetID(ShortHash(s, 0))
+}
+
+// Put stores a snippet and returns its ID. Storing the same content twice keeps one copy.
+func (st *SnippetStore) Put(s string) SnippetID {
+	id := SnippetIDOf(s)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if _, ok := st.snippets[id]; !ok {
+		st.snippets[id] = s
+	}
+	return id
+}
+
+// Get returns the snippet stored under id.
+func (st *SnippetStore) Get(id SnippetID) (string, bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	s, ok := st.snippets[id]
+	return s, ok
+}
+
+// Dedup stores every item and returns their IDs in order, so repeated items share one copy.
+func (st *SnippetStore) Dedup(items []string) []SnippetID {
+	ids := make([]SnippetID, len(items))
+	for i, item := range items {
+		ids[i] = st.Put(item)
+	}
+	return ids
+}
+
+// Len returns the number of distinct snippets in the store.
+func (st *SnippetStore) Len() int {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return len(st.snippets)
+}
+
+// Save writes every snippet to dir, one file per snippet named by its ID.
+func (st *SnippetStore) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	for id, s := range st.snippets {
+		path := filepath.Join(dir, string(id))
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadSnippetStore reads a store previously written with Save.
+func LoadSnippetStore(dir string) (*SnippetStore, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	st := NewSnippetStore()
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		id := SnippetID(entry.Name())
+		if SnippetIDOf(string(data)) != id {
+			return nil, fmt.Errorf("snippet %s does not match its content hash", id)
+		}
+		st.snippets[id] = string(data)
+	}
+	return st, nil
+}
@@ -0,0 +1,171 @@
+package purse
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// SourceMap maps positions in the output of a transformation back to its input, so
+// errors found in preprocessed text can be reported at their original location.
+type SourceMap struct {
+	input    string
+	output   string
+	segments []mapSegment
+}
+
+// mapSegment records that output[out:out+n] came from input starting at in. Copied
+// segments map byte for byte; generated segments map every byte to in.
+type mapSegment struct {
+	out       int
+	in        int
+	n         int
+	generated bool
+}
+
+// Original returns the input byte offset that produced the output byte at offset.
+// Offsets past the end of the output map to the end of the input.
+func (m *SourceMap) Original(offset int) int {
+	if offset < 0 {
+		return 0
+	}
+	i := sort.Search(len(m.segments), func(i int) bool {
+		return m.segments[i].out+m.segments[i].n > offset
+	})
+	if i == len(m.segments) {
+		return len(m.input)
+	}
+	seg := m.segments[i]
+	if seg.generated || offset < seg.out {
+		return seg.in
+	}
+	return seg.in + offset - seg.out
+}
+
+// OriginalLineCol converts a 1-based output line and rune column, as produced by
+// LineCol, into the matching input line and column.
+func (m *SourceMap) OriginalLineCol(line, col int) (int, int) {
+	return LineCol(m.input, m.Original(lineColOffset(m.output, line, col)))
+}
+
+// lineColOffset is the inverse of LineCol, clamping positions that fall outside s.
+func lineColOffset(s string, line, col int) int {
+	offset := 0
+	for ; line > 1; line-- {
+		i := strings.IndexByte(s[offset:], '\n')
+		if i == -1 {
+			return len(s)
+		}
+		offset += i + 1
+	}
+	for ; col > 1 && offset < len(s) && s[offset] != '\n'; col-- {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// sourceMapBuilder assembles an output string together with its SourceMap.
+type sourceMapBuilder struct {
+	input string
+	sb    strings.Builder
+	segs  []mapSegment
+}
+
+// copy appends input[in:in+n] to the output.
+func (b *sourceMapBuilder) copy(in, n int) {
+	if n == 0 {
+		return
+	}
+	out := b.sb.Len()
+	b.sb.WriteString(b.input[in : in+n])
+	// Extend the previous segment when this copy continues it
+	if k := len(b.segs) - 1; k >= 0 && !b.segs[k].generated && b.segs[k].out+b.segs[k].n == out && b.segs[k].in+b.segs[k].n == in {
+		b.segs[k].n += n
+		return
+	}
+	b.segs = append(b.segs, mapSegment{out: out, in: in, n: n})
+}
+
+// generate appends text that has no exact counterpart in the input, attributing it
+// to the input offset in.
+func (b *sourceMapBuilder) generate(in int, text string) {
+	if text == "" {
+		return
+	}
+	b.segs = append(b.segs, mapSegment{out: b.sb.Len(), in: in, n: len(text), generated: true})
+	b.sb.WriteString(text)
+}
+
+func (b *sourceMapBuilder) result() (string, *SourceMap) {
+	out := b.sb.String()
+	return out, &SourceMap{input: b.input, output: out, segments: b.segs}
+}
+
+// eachLineAt is eachLine with the byte offset at which each line starts.
+func eachLineAt(s string, fn func(offset int, line string)) {
+	offset := 0
+	eachLine(s, func(_ int, line string) {
+		fn(offset, line)
+		offset += len(line) + 1
+	})
+}
+
+// RemoveEmptyLinesMapped is RemoveEmptyLines that also returns a SourceMap.
+func RemoveEmptyLinesMapped(input string) (string, *SourceMap) {
+	b := &sourceMapBuilder{input: input}
+	prevEnd := -1
+	eachLineAt(input, func(offset int, line string) {
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+		if prevEnd != -1 {
+			// The separator is the newline that ended the previous kept line
+			b.copy(prevEnd, 1)
+		}
+		b.copy(offset, len(line))
+		prevEnd = offset + len(line)
+	})
+	return b.result()
+}
+
+// FlattenMapped is Flatten that also returns a SourceMap.
+func FlattenMapped(str string) (string, *SourceMap) {
+	b := &sourceMapBuilder{input: str}
+	eachLineAt(str, func(offset int, line string) {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		b.copy(offset+indent, len(line)-indent)
+	})
+	return b.result()
+}
+
+// DedentMapped is Dedent that also returns a SourceMap.
+func DedentMapped(s string) (string, *SourceMap) {
+	b := &sourceMapBuilder{input: s}
+	indent := len(commonIndent(s))
+	eachLineAt(s, func(offset int, line string) {
+		if offset > 0 {
+			b.copy(offset-1, 1)
+		}
+		if strings.TrimSpace(line) != "" {
+			b.copy(offset+indent, len(line)-indent)
+		}
+	})
+	return b.result()
+}
+
+// ReplaceBetweenMapped is ReplaceBetween that also returns a SourceMap. Text produced
+// by fn maps to the start of the inner text it replaced.
+func ReplaceBetweenMapped(s, start, end string, fn func(inner string) string) (string, *SourceMap) {
+	b := &sourceMapBuilder{input: s}
+	pos := 0
+	for _, span := range FindAll(s, start, end) {
+		inner := span.Start + len(start)
+		b.copy(pos, inner-pos)
+		b.generate(inner, fn(span.Text[len(start):len(span.Text)-len(end)]))
+		b.copy(span.End-len(end), len(end))
+		pos = span.End
+	}
+	b.copy(pos, len(s)-pos)
+	return b.result()
+}
@@ -0,0 +1,78 @@
+package purse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is wrapped by the Try functions when the text they were asked to change
+// or find is not present, so callers can check for it with errors.Is.
+var ErrNotFound = errors.New("not found")
+
+// Must returns v, panicking if err is not nil. It turns any Try function into one that
+// fails loudly, as in Must(TryReplaceLastSubStr(s, old, new)).
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// TryReplaceLastSubStr is ReplaceLastSubStr, but it returns an error wrapping
+// ErrNotFound instead of the unchanged input when old does not occur in s.
+func TryReplaceLastSubStr(s, old, new string) (string, error) {
+	if !strings.Contains(s, old) {
+		return "", fmt.Errorf("substring %q: %w", old, ErrNotFound)
+	}
+	return ReplaceLastSubStr(s, old, new), nil
+}
+
+// TryReplaceFirstInstanceOf is ReplaceFirstInstanceOf, but it returns an error
+// wrapping ErrNotFound when old does not occur in s.
+func TryReplaceFirstInstanceOf(s, old, new string) (string, error) {
+	if !strings.Contains(s, old) {
+		return "", fmt.Errorf("substring %q: %w", old, ErrNotFound)
+	}
+	return ReplaceFirstInstanceOf(s, old, new), nil
+}
+
+// TryReplaceLastInstanceOf is ReplaceLastInstanceOf, but it returns an error wrapping
+// ErrNotFound when old does not occur in s.
+func TryReplaceLastInstanceOf(s, old, new string) (string, error) {
+	if !strings.Contains(s, old) {
+		return "", fmt.Errorf("substring %q: %w", old, ErrNotFound)
+	}
+	return ReplaceLastInstanceOf(s, old, new), nil
+}
+
+// TryTargetSearch is TargetSearch, but its error says which of the two searches failed.
+func TryTargetSearch(input, primarySearch, secondarySearch string) (string, error) {
+	start := strings.Index(input, primarySearch)
+	if start == -1 {
+		return "", fmt.Errorf("primary search %q: %w", primarySearch, ErrNotFound)
+	}
+	if !strings.Contains(input[start:], secondarySearch) {
+		return "", fmt.Errorf("secondary search %q after %q: %w", secondarySearch, primarySearch, ErrNotFound)
+	}
+	found, _ := TargetSearch(input, primarySearch, secondarySearch)
+	return found, nil
+}
+
+// TryReplaceFirstLine is ReplaceFirstLine, but it refuses an empty input rather than
+// treating it as a single empty line.
+func TryReplaceFirstLine(input, newLine string) (string, error) {
+	if input == "" {
+		return "", fmt.Errorf("first line of empty input: %w", ErrNotFound)
+	}
+	return ReplaceFirstLine(input, newLine), nil
+}
+
+// TryReplaceLastLine is ReplaceLastLine, but it refuses an empty input rather than
+// treating it as a single empty line.
+func TryReplaceLastLine(input, newLine string) (string, error) {
+	if input == "" {
+		return "", fmt.Errorf("last line of empty input: %w", ErrNotFound)
+	}
+	return ReplaceLastLine(input, newLine), nil
+}
@@ -0,0 +1,111 @@
+package purse
+
+import (
+	"strings"
+)
+
+// Align describes how text is positioned within a fixed-width slot.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// TableOpts configures AlignColumns.
+type TableOpts struct {
+	Align []Align // per-column alignment, missing columns default to AlignLeft
+	Sep   string  // placed between columns, defaults to a single space
+}
+
+// AlignColumns renders rows as aligned columns, one row per line. Rows may have
+// fewer cells than others; they end after their last cell.
+func AlignColumns(rows [][]string, opts TableOpts) string {
+	sep := opts.Sep
+	if sep == "" {
+		sep = " "
+	}
+	widths := make([]int, 0)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := VisualWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		// a row stops at its own last cell, so short rows are not padded with empty columns
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			align := AlignLeft
+			if i < len(opts.Align) {
+				align = opts.Align[i]
+			}
+			// the last cell is left unpadded so lines carry no trailing spaces
+			if i == len(row)-1 && align == AlignLeft {
+				cells[i] = cell
+				continue
+			}
+			cells[i] = PadToWidth(cell, widths[i], align)
+		}
+		lines = append(lines, strings.Join(cells, sep))
+	}
+	return JoinLines(lines)
+}
+
+// LineFormatter renders values into fixed-width, aligned fields.
+type LineFormatter struct {
+	fields []lineField
+	sep    string
+}
+
+type lineField struct {
+	name  string
+	width int
+	align Align
+}
+
+// NewLineFormatter creates a LineFormatter that places sep between fields.
+func NewLineFormatter(sep string) *LineFormatter {
+	return &LineFormatter{sep: sep}
+}
+
+// AddField appends a field. A width of zero leaves the field unpadded and untruncated.
+func (f *LineFormatter) AddField(name string, width int, align Align) *LineFormatter {
+	f.fields = append(f.fields, lineField{name: name, width: width, align: align})
+	return f
+}
+
+// Format renders values in the order the fields were added. Missing values render blank.
+func (f *LineFormatter) Format(values ...string) string {
+	cells := make([]string, len(f.fields))
+	for i, field := range f.fields {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		cells[i] = field.render(value)
+	}
+	return strings.Join(cells, f.sep)
+}
+
+// FormatMap renders values looked up by field name.
+func (f *LineFormatter) FormatMap(values map[string]string) string {
+	cells := make([]string, len(f.fields))
+	for i, field := range f.fields {
+		cells[i] = field.render(values[field.name])
+	}
+	return strings.Join(cells, f.sep)
+}
+
+func (field lineField) render(value string) string {
+	if field.width <= 0 {
+		return value
+	}
+	return FitLabel(value, field.width, field.align)
+}
@@ -0,0 +1,76 @@
+package purse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tokenizer splits strings into tokens on a set of delimiters while respecting quotes and escapes.
+type Tokenizer struct {
+	Delims string // characters that separate tokens
+	Quotes string // characters that open and close quoted sections
+	Escape rune   // escapes the next character, zero disables escaping
+	// RawQuotes lists quote characters whose contents are taken literally, with no escapes.
+	RawQuotes string
+}
+
+// ShellTokenizer splits like a POSIX shell: whitespace separated words, single and
+// double quotes, and backslash escapes, with no escapes inside single quotes.
+var ShellTokenizer = Tokenizer{Delims: " \t\n", Quotes: "\"'", Escape: '\\', RawQuotes: "'"}
+
+// SplitArgs splits a string into shell-style words.
+func SplitArgs(s string) ([]string, error) {
+	return ShellTokenizer.Split(s)
+}
+
+// Split breaks s into tokens. Quotes are removed from the output, and an empty quoted
+// section still produces a token.
+func (t Tokenizer) Split(s string) ([]string, error) {
+	var tokens []string
+	var sb strings.Builder
+	inToken := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			// inside double quotes only quotes and the escape itself can be escaped
+			if quote != 0 && r != quote && r != t.Escape {
+				sb.WriteRune(t.Escape)
+			}
+			sb.WriteRune(r)
+			escaped = false
+		case t.Escape != 0 && r == t.Escape && !strings.ContainsRune(t.RawQuotes, quote):
+			escaped = true
+			inToken = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			sb.WriteRune(r)
+		case strings.ContainsRune(t.Quotes, r):
+			quote = r
+			inToken = true
+		case strings.ContainsRune(t.Delims, r):
+			if inToken {
+				tokens = append(tokens, sb.String())
+				sb.Reset()
+				inToken = false
+			}
+		default:
+			sb.WriteRune(r)
+			inToken = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing escape character %c", t.Escape)
+	}
+	if inToken {
+		tokens = append(tokens, sb.String())
+	}
+	return tokens, nil
+}
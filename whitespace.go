@@ -0,0 +1,182 @@
+package purse
+
+import (
+	"strings"
+)
+
+// NormalizeWhitespace collapses every run of spaces and tabs into a single space,
+// leaving newlines in place.
+func NormalizeWhitespace(s string) string {
+	var sb strings.Builder
+	inRun := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			if !inRun {
+				sb.WriteByte(' ')
+			}
+			inRun = true
+			continue
+		}
+		inRun = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// TrimTrailingSpaces removes trailing spaces and tabs from every line of a string.
+func TrimTrailingSpaces(s string) string {
+	return MapLines(s, func(line string, _ int) string {
+		return strings.TrimRight(line, " \t")
+	})
+}
+
+// ExpandTabs replaces tabs with spaces up to the next tab stop, with stops every width columns.
+func ExpandTabs(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	return MapLines(s, func(line string, _ int) string {
+		if !strings.Contains(line, "\t") {
+			return line
+		}
+		var sb strings.Builder
+		col := 0
+		for _, r := range line {
+			if r == '\t' {
+				n := width - col%width
+				sb.WriteString(strings.Repeat(" ", n))
+				col += n
+				continue
+			}
+			sb.WriteRune(r)
+			col += RuneWidth(r)
+		}
+		return sb.String()
+	})
+}
+
+// UnexpandTabs converts the leading whitespace of every line into tabs, with tab stops
+// every width columns, like unexpand(1). Spaces that do not reach a full stop are kept.
+func UnexpandTabs(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	return MapLines(s, func(line string, _ int) string {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		col := 0
+		for _, r := range line[:indent] {
+			if r == '\t' {
+				col += width - col%width
+				continue
+			}
+			col++
+		}
+		return strings.Repeat("\t", col/width) + strings.Repeat(" ", col%width) + line[indent:]
+	})
+}
+
+// WhitespaceProblem identifies the kind of issue reported by LintWhitespace.
+type WhitespaceProblem int
+
+const (
+	TrailingWhitespace  WhitespaceProblem = iota // spaces or tabs at the end of a line
+	SpaceBeforeTab                               // a tab following a space in the indentation
+	MixedIndentation                             // indentation that disagrees with the document's unit
+	MissingFinalNewline                          // a non-empty document that does not end in "\n"
+)
+
+// WhitespaceIssue is one problem found by LintWhitespace on a 1-based line.
+type WhitespaceIssue struct {
+	Line    int
+	Problem WhitespaceProblem
+	Msg     string
+}
+
+// LintWhitespace reports trailing whitespace, tabs after spaces in indentation,
+// indentation that disagrees with the unit DetectIndentUnit finds for the document,
+// and a missing final newline, in line order.
+func LintWhitespace(s string) []WhitespaceIssue {
+	var issues []WhitespaceIssue
+	unit := DetectIndentUnit(s)
+	lines := MakeLines(s)
+	for i, line := range lines {
+		if i == len(lines)-1 && line == "" {
+			break
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		switch {
+		case strings.Contains(indent, " \t"):
+			issues = append(issues, WhitespaceIssue{Line: i + 1, Problem: SpaceBeforeTab, Msg: "tab after space in indentation"})
+		case unit == "\t" && strings.HasPrefix(indent, " ") && strings.TrimSpace(line) != "":
+			issues = append(issues, WhitespaceIssue{Line: i + 1, Problem: MixedIndentation, Msg: "indented with spaces in a tab-indented document"})
+		case unit != "" && unit != "\t" && strings.Contains(indent, "\t"):
+			issues = append(issues, WhitespaceIssue{Line: i + 1, Problem: MixedIndentation, Msg: "indented with tabs in a space-indented document"})
+		}
+		if strings.TrimRight(line, " \t") != line {
+			issues = append(issues, WhitespaceIssue{Line: i + 1, Problem: TrailingWhitespace, Msg: "trailing whitespace"})
+		}
+	}
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		issues = append(issues, WhitespaceIssue{Line: len(lines), Problem: MissingFinalNewline, Msg: "missing final newline"})
+	}
+	return issues
+}
+
+// FixOption configures FixWhitespace.
+type FixOption func(*fixConfig)
+
+type fixConfig struct {
+	unit     string
+	tabWidth int
+}
+
+// IndentWith makes FixWhitespace indent with unit, "\t" or spaces, instead of the unit
+// detected from the document.
+func IndentWith(unit string) FixOption {
+	return func(c *fixConfig) {
+		c.unit = unit
+	}
+}
+
+// TabWidth sets how many columns a tab stands for when FixWhitespace converts
+// indentation. It defaults to 4.
+func TabWidth(n int) FixOption {
+	return func(c *fixConfig) {
+		if n > 0 {
+			c.tabWidth = n
+		}
+	}
+}
+
+// FixWhitespace corrects what LintWhitespace reports: trailing whitespace is removed,
+// indentation is rewritten in a single unit keeping its visual width, and a final
+// newline is added to a non-empty document.
+func FixWhitespace(s string, opts ...FixOption) string {
+	cfg := fixConfig{unit: DetectIndentUnit(s), tabWidth: 4}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	fixed := MapLines(s, func(line string, _ int) string {
+		line = strings.TrimRight(line, " \t")
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == 0 || cfg.unit == "" {
+			return line
+		}
+		col := 0
+		for _, r := range line[:indent] {
+			if r == '\t' {
+				col += cfg.tabWidth - col%cfg.tabWidth
+				continue
+			}
+			col++
+		}
+		if cfg.unit == "\t" {
+			return strings.Repeat("\t", col/cfg.tabWidth) + strings.Repeat(" ", col%cfg.tabWidth) + line[indent:]
+		}
+		return strings.Repeat(" ", col) + line[indent:]
+	})
+	if fixed == "" {
+		return ""
+	}
+	return EnsureTrailingNewline(fixed)
+}
@@ -0,0 +1,351 @@
+package purse
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRanges lists the East Asian wide and fullwidth ranges, plus emoji, that occupy two columns.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},
+	{0x231A, 0x231B},
+	{0x2329, 0x232A},
+	{0x23E9, 0x23EC},
+	{0x23F0, 0x23F0},
+	{0x23F3, 0x23F3},
+	{0x25FD, 0x25FE},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x267F, 0x267F},
+	{0x2693, 0x2693},
+	{0x26A1, 0x26A1},
+	{0x26AA, 0x26AB},
+	{0x26BD, 0x26BE},
+	{0x26C4, 0x26C5},
+	{0x26CE, 0x26CE},
+	{0x26D4, 0x26D4},
+	{0x26EA, 0x26EA},
+	{0x26F2, 0x26F3},
+	{0x26F5, 0x26F5},
+	{0x26FA, 0x26FA},
+	{0x26FD, 0x26FD},
+	{0x2705, 0x2705},
+	{0x270A, 0x270B},
+	{0x2728, 0x2728},
+	{0x274C, 0x274C},
+	{0x274E, 0x274E},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27B0, 0x27B0},
+	{0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C},
+	{0x2B50, 0x2B50},
+	{0x2B55, 0x2B55},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xA960, 0xA97F},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE10, 0xFE19},
+	{0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x16FE0, 0x16FE4},
+	{0x17000, 0x18CFF},
+	{0x1B000, 0x1B2FF},
+	{0x1F004, 0x1F004},
+	{0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E},
+	{0x1F191, 0x1F19A},
+	{0x1F200, 0x1F251},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F7E0, 0x1F7EB},
+	{0x1F90C, 0x1F9FF},
+	{0x1FA70, 0x1FAFF},
+	{0x20000, 0x2FFFD},
+	{0x30000, 0x3FFFD},
+}
+
+// RuneWidth returns the number of terminal columns a rune occupies.
+func RuneWidth(r rune) int {
+	if r < 0x20 || (r >= 0x7F && r < 0xA0) {
+		return 0
+	}
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	if r < 0x1100 {
+		return 1
+	}
+	lo, hi := 0, len(wideRanges)-1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < wideRanges[mid][0]:
+			hi = mid - 1
+		case r > wideRanges[mid][1]:
+			lo = mid + 1
+		default:
+			return 2
+		}
+	}
+	return 1
+}
+
+// WidthOption configures VisualWidth, PadToWidth and Truncate.
+type WidthOption func(*widthConfig)
+
+type widthConfig struct {
+	countANSI bool
+}
+
+// CountANSI makes ANSI escape sequences count as ordinary text instead of being skipped,
+// for output that is not going to a terminal.
+func CountANSI() WidthOption {
+	return func(c *widthConfig) {
+		c.countANSI = true
+	}
+}
+
+func newWidthConfig(opts []WidthOption) widthConfig {
+	var cfg widthConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// escapeLen returns the length of the escape sequence at the start of s that takes up no columns.
+func (c widthConfig) escapeLen(s string) int {
+	if c.countANSI {
+		return 0
+	}
+	return ansiLen(s)
+}
+
+// width measures s in terminal columns.
+func (c widthConfig) width(s string) int {
+	width := 0
+	for i := 0; i < len(s); {
+		if n := c.escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += RuneWidth(r)
+		i += size
+	}
+	return width
+}
+
+// cut returns the longest prefix of s that fits in width columns,
+// keeping any combining marks attached to the last character.
+func (c widthConfig) cut(s string, width int) string {
+	used := 0
+	for i := 0; i < len(s); {
+		if n := c.escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := RuneWidth(r)
+		if w > 0 && used+w > width {
+			return s[:i]
+		}
+		used += w
+		i += size
+	}
+	return s
+}
+
+// VisualWidth returns the number of terminal columns a string occupies,
+// counting wide characters as two columns and combining marks and ANSI escape
+// sequences as none.
+func VisualWidth(s string, opts ...WidthOption) int {
+	return newWidthConfig(opts).width(s)
+}
+
+// PadToWidth pads a string with spaces to the given display width using the given alignment.
+func PadToWidth(s string, width int, align Align, opts ...WidthOption) string {
+	gap := width - VisualWidth(s, opts...)
+	if gap <= 0 {
+		return s
+	}
+	switch align {
+	case AlignRight:
+		return strings.Repeat(" ", gap) + s
+	case AlignCenter:
+		left := gap / 2
+		return strings.Repeat(" ", left) + s + strings.Repeat(" ", gap-left)
+	default:
+		return s + strings.Repeat(" ", gap)
+	}
+}
+
+// cutToWidth returns the longest prefix of s that fits in width columns, skipping ANSI escapes.
+func cutToWidth(s string, width int) string {
+	return widthConfig{}.cut(s, width)
+}
+
+// Truncate shortens a string to at most width columns, ending it with ellipsis when cut.
+// ANSI escape sequences in the removed text are kept so colors are still reset,
+// unless CountANSI is given.
+func Truncate(s string, width int, ellipsis string, opts ...WidthOption) string {
+	if width <= 0 {
+		return ""
+	}
+	cfg := newWidthConfig(opts)
+	if cfg.width(s) <= width {
+		return s
+	}
+	room := width - cfg.width(ellipsis)
+	if room <= 0 {
+		return cfg.cut(ellipsis, width)
+	}
+	cut := cfg.cut(s, room)
+	if cfg.countANSI {
+		return cut + ellipsis
+	}
+	return cut + ellipsis + ansiCodes(s[len(cut):])
+}
+
+// SnipAtWordBoundary truncates a string to at most max columns, cutting at the last
+// word boundary that fits. An optional ellipsis is appended when the string is cut.
+// A single word longer than max is cut mid-word.
+func SnipAtWordBoundary(s string, max int, ellipsis ...string) string {
+	if VisualWidth(s) <= max {
+		return s
+	}
+	suffix := strings.Join(ellipsis, "")
+	room := max - VisualWidth(suffix)
+	if room <= 0 {
+		return cutToWidth(suffix, max)
+	}
+	cut := cutToWidth(s, room)
+	next, _ := utf8.DecodeRuneInString(s[len(cut):])
+	if !unicode.IsSpace(next) {
+		if i := strings.LastIndexFunc(cut, unicode.IsSpace); i != -1 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRightFunc(cut, unicode.IsSpace) + suffix
+}
+
+// FitLabel truncates and pads a string so it fills exactly width columns.
+func FitLabel(s string, width int, align Align) string {
+	return PadToWidth(Truncate(s, width, "…"), width, align)
+}
+
+// JoinWrapped joins items with sep, starting a new line whenever the next item would push
+// the current line past width columns. Lines that break keep the separator without
+// trailing spaces, and that separator counts toward the width. Items are never split, so
+// an item that does not fit in width columns together with its separator sits on a line
+// of its own and is the only way a line can exceed width.
+func JoinWrapped(items []string, sep string, width int) string {
+	var lines []string
+	current := ""
+	lineSep := strings.TrimRightFunc(sep, unicode.IsSpace)
+	for i, item := range items {
+		if i == 0 {
+			current = item
+			continue
+		}
+		candidate := current + sep + item
+		// Unless this is the last item, the line may still break after it
+		if i < len(items)-1 {
+			candidate += lineSep
+		}
+		if VisualWidth(candidate) > width {
+			lines = append(lines, current+lineSep)
+			current = item
+			continue
+		}
+		current += sep + item
+	}
+	if len(items) > 0 {
+		lines = append(lines, current)
+	}
+	return JoinLines(lines)
+}
+
+// mapWords replaces every run of non-space characters in s with fn of it, keeping the
+// whitespace between them as it was.
+func mapWords(s string, fn func(word string) string) string {
+	var sb strings.Builder
+	for s != "" {
+		start := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsSpace(r) })
+		if start == -1 {
+			sb.WriteString(s)
+			break
+		}
+		sb.WriteString(s[:start])
+		s = s[start:]
+		end := strings.IndexFunc(s, unicode.IsSpace)
+		if end == -1 {
+			end = len(s)
+		}
+		sb.WriteString(fn(s[:end]))
+		s = s[end:]
+	}
+	return sb.String()
+}
+
+// splitToWidth breaks s into pieces of at most width columns. A character wider than
+// width gets a piece of its own rather than being dropped.
+func splitToWidth(s string, width int) []string {
+	var pieces []string
+	for s != "" {
+		piece := cutToWidth(s, width)
+		if piece == "" {
+			_, size := utf8.DecodeRuneInString(s)
+			piece = s[:size]
+		}
+		pieces = append(pieces, piece)
+		s = s[len(piece):]
+	}
+	return pieces
+}
+
+// InsertBreakOpportunities inserts sep every every columns inside words longer than
+// that, so long unbroken tokens such as URLs and hashes can wrap. Use "\u200b" (a zero
+// width space) for terminals or "<wbr>" for HTML. Whitespace is left untouched.
+func InsertBreakOpportunities(s string, every int, sep string) string {
+	if every <= 0 || sep == "" {
+		return s
+	}
+	return mapWords(s, func(word string) string {
+		if VisualWidth(word) <= every {
+			return word
+		}
+		return strings.Join(splitToWidth(word, every), sep)
+	})
+}
+
+// HyphenateLongWords breaks every word wider than max columns into hyphenated pieces
+// separated by spaces, each at most max columns including its hyphen, so that
+// whitespace-based wrapping such as JoinWrapped can fit them. A max below 2 leaves s
+// unchanged.
+func HyphenateLongWords(s string, max int) string {
+	if max < 2 {
+		return s
+	}
+	return mapWords(s, func(word string) string {
+		if VisualWidth(word) <= max {
+			return word
+		}
+		pieces := splitToWidth(word, max-1)
+		last := len(pieces) - 1
+		// A final piece that fits beside the hyphen is not worth a line of its own
+		if VisualWidth(pieces[last]) == 1 && last > 0 {
+			pieces[last-1] += pieces[last]
+			pieces = pieces[:last]
+		}
+		return strings.Join(pieces, "- ")
+	})
+}